@@ -0,0 +1,187 @@
+package chocolate
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Bar is a handle to a bar of a chocolate. It is returned by MakeBar and
+// Chocolate.Bar and saves looking up the bar by its name for every call
+type Bar struct {
+	c    *Chocolate
+	name string
+	bar  *chocolateBar
+}
+
+func (b *Bar) Name() string { return b.name }
+
+func (b *Bar) Hide()                 { b.bar.hide() }
+func (b *Bar) Unhide()               { b.bar.unhide() }
+func (b *Bar) IsHidden() bool        { return b.bar.isHidden() }
+func (b *Bar) IsAutoHidden() bool    { return b.bar.isAutoHidden() }
+func (b *Bar) SetCanHide(v bool)     { b.bar.setCanHide(v) }
+func (b *Bar) SetHidePriority(v int) { b.bar.setHidePriority(v) }
+func (b *Bar) SetMinSize(width, height int) {
+	b.bar.setMinSize(width, height)
+}
+func (b *Bar) SetZIndex(z int)                   { b.bar.setZIndex(z) }
+func (b *Bar) SetOverflow(policy OverflowPolicy) { b.bar.setOverflow(policy) }
+
+// Select selects the model of the bar
+func (b *Bar) Select(model string) error {
+	return b.c.SelectModel(model, b.name)
+}
+
+// Selected returns the name of the selected model
+func (b *Bar) Selected() string { return b.bar.selectedName() }
+
+func (b *Bar) SelectStyle(style FlavourStyleSelector) { b.bar.selectStyle(style) }
+
+// Style returns the current style of the shown model
+func (b *Bar) Style() lipgloss.Style {
+	if b.bar.current == nil {
+		return lipgloss.NewStyle()
+	}
+	return b.bar.current.currentStyle()
+}
+
+// Rect returns the position relative to the chocolate's origin and the size
+// of the bar, as resolved by the last rendering
+func (b *Bar) Rect() (x, y, width, height int) {
+	ox, oy := b.c.rootModel.contentOffset()
+	return b.bar.xpos() + ox, b.bar.ypos() + oy, b.bar.width(), b.bar.height()
+}
+
+func (b *Bar) AddThemeModifier(model string, style FlavourStyleSelector, modifiers ...ThemeStyleModifier) error {
+	return b.c.AddThemeModifier(b.name, model, style, modifiers...)
+}
+
+func (b *Bar) AddModel(model BarModel, name string, flavoured bool, styles ...FlavourStyleSelector) error {
+	return b.c.AddModelBarModel(model, name, b.name, flavoured, styles...)
+}
+
+func (b *Bar) AddView(viewer BarViewer, name string, flavoured bool, styles ...FlavourStyleSelector) error {
+	return b.c.AddViewBarModel(viewer, name, b.name, flavoured, styles...)
+}
+
+func (b *Bar) AddTeaModel(model tea.Model, name string, flavoured bool, styles ...FlavourStyleSelector) error {
+	return b.c.AddTeaModelBarModel(model, name, b.name, flavoured, styles...)
+}
+
+func (b *Bar) MakeText(name string, flavoured bool, styles ...FlavourStyleSelector) (*TextModel, error) {
+	return b.c.MakeText(name, b.name, flavoured, styles...)
+}
+
+func (b *Bar) MakeChocolate(name string, flavoured bool, styles ...FlavourStyleSelector) (*Chocolate, error) {
+	return b.c.MakeChocolate(name, b.name, flavoured, styles...)
+}
+
+func (b *Bar) MakeScroll(viewer BarViewer, name string, flavoured bool, styles ...FlavourStyleSelector) (*ScrollModel, error) {
+	return b.c.MakeScroll(viewer, name, b.name, flavoured, styles...)
+}
+
+// barKey is the key of a bar in the chocolate and its layout.
+// The names of the bars are case insensitive
+func barKey(name string) string { return strings.ToLower(name) }
+
+// Bar returns the handle of the bar
+func (c *Chocolate) Bar(name string) (*Bar, error) {
+	b, err := c.getBar(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bar{
+		c:    c,
+		name: barKey(name),
+		bar:  b,
+	}, nil
+}
+
+// Bars returns the handles of all bars in the order they were made
+func (c *Chocolate) Bars() []*Bar {
+	ret := make([]*Bar, 0, len(c.root.order))
+	for _, name := range c.root.order {
+		if b, err := c.Bar(name); err == nil {
+			ret = append(ret, b)
+		}
+	}
+
+	return ret
+}
+
+// validateDocument checks that all bars referenced by the document exist.
+// Documents with a bars section have to declare every bar they use, the
+// bars of included files and fragments are declared by them. Without a
+// bars section the targets of the constraints, the bars of the splits and
+// the models make the bars, so only sources and containers are checked.
+// Bars made before loading the document are always known
+func (c *Chocolate) validateDocument(doc *layoutDocument) error {
+	known := map[string]bool{"super": true}
+	add := func(name string) { known[barKey(name)] = true }
+	for name := range c.bars {
+		add(name)
+	}
+	for _, bar := range doc.Bars {
+		add(bar.name)
+	}
+	for _, name := range doc.provided {
+		add(name)
+	}
+	if !doc.declared {
+		for _, con := range doc.Constraints {
+			for _, name := range con.barNames() {
+				add(name)
+			}
+		}
+		for _, split := range doc.Splits {
+			add(split.First)
+			add(split.Second)
+		}
+		for _, md := range doc.Models {
+			add(md.Bar)
+		}
+	}
+
+	check := func(name string, what string) error {
+		if name == "" || known[barKey(name)] {
+			return nil
+		}
+		return fmt.Errorf("%w: '%s' used as %s", ErrUnknownBar, name, what)
+	}
+
+	for _, con := range doc.Constraints {
+		if err := check(con.Target, "target"); err != nil {
+			return err
+		}
+		for _, name := range con.Bars {
+			if err := check(name, "no_overlap bar"); err != nil {
+				return err
+			}
+		}
+		if err := check(con.Source, "source"); err != nil {
+			return err
+		}
+		if err := check(con.Inside, "container"); err != nil {
+			return err
+		}
+	}
+	for _, split := range doc.Splits {
+		if err := check(split.First, "first bar of split '"+split.Name+"'"); err != nil {
+			return err
+		}
+		if err := check(split.Second, "second bar of split '"+split.Name+"'"); err != nil {
+			return err
+		}
+	}
+	for _, md := range doc.Models {
+		if err := check(md.Bar, "bar of model '"+md.Name+"'"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package chocolate
+
+import (
+	"errors"
+	"testing"
+)
+
+const mixedCaseLayout = `{
+	"bars": {
+		"Header": {"models": [{"name": "title", "text": "title"}]},
+		"Body": {"models": [{"name": "text", "text": "text"}]}
+	},
+	"constraints": [
+		{"target": "Header", "target_attribute": "height", "relation": "eq", "constant": 3, "strength": "required"},
+		{"target": "Header", "target_attribute": "width", "source": "super", "source_attribute": "width", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "Body", "target_attribute": "ystart", "source": "Header", "source_attribute": "yend", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "Body", "target_attribute": "yend", "source": "super", "source_attribute": "yend", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "Body", "target_attribute": "width", "source": "super", "source_attribute": "width", "relation": "eq", "multiplier": 1, "strength": "required"}
+	]
+}`
+
+func TestBarNamesAreCaseInsensitive(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+	c.View()
+
+	if got := len(c.Bars()); got != 2 {
+		t.Fatalf("Bars() returned %d bars, want 2", got)
+	}
+	for _, name := range []string{"header", "Header", "HEADER"} {
+		b, err := c.Bar(name)
+		if err != nil {
+			t.Fatalf("Bar(%q): %v", name, err)
+		}
+		if _, _, w, h := b.Rect(); w != 40 || h != 3 {
+			t.Errorf("Bar(%q).Rect() size = %dx%d, want 40x3", name, w, h)
+		}
+	}
+	body, _ := c.Bar("body")
+	if x, y, w, h := body.Rect(); x != 0 || y != 3 || w != 40 || h != 7 {
+		t.Errorf("body rect = %d,%d %dx%d, want 0,3 40x7", x, y, w, h)
+	}
+	if _, err := c.Explain("header"); err != nil {
+		t.Errorf("Explain(header): %v", err)
+	}
+}
+
+func TestUnknownNames(t *testing.T) {
+	c := NewChocolate()
+	b := c.MakeBar("bar", false)
+	if _, err := b.MakeText("text", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Hide", c.Hide("nope"), ErrUnknownBar},
+		{"SelectModel bar", c.SelectModel("text", "nope"), ErrUnknownBar},
+		{"SelectModel model", c.SelectModel("nope", "bar"), ErrUnknownModel},
+		{"AddThemeModifier", c.AddThemeModifier("bar", "nope", TS_DEFAULT, Bold(true)), ErrUnknownModel},
+		{"SetTitle", c.SetTitle("bar", "nope", TS_DEFAULT, BorderLabel{}), ErrUnknownModel},
+		{"SetModelOverflow", c.SetModelOverflow("bar", "nope", OVERFLOW_CLIP), ErrUnknownModel},
+		{"Bar.AddThemeModifier", b.AddThemeModifier("nope", TS_DEFAULT, Bold(true)), ErrUnknownModel},
+		{"AddThemeModifier known", c.AddThemeModifier("BAR", "TEXT", TS_DEFAULT, Bold(true)), nil},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) || (tt.want == nil && tt.err != nil) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+
+	if _, err := c.MakeText("text", "nope", true); !errors.Is(err, ErrUnknownBar) {
+		t.Errorf("MakeText: got %v, want %v", err, ErrUnknownBar)
+	}
+	if _, err := c.IsHidden("nope"); !errors.Is(err, ErrUnknownBar) {
+		t.Errorf("IsHidden: got %v, want %v", err, ErrUnknownBar)
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		ok     bool
+	}{
+		{
+			name:   "without bars section targets make bars",
+			layout: `{"constraints": [{"target": "content", "target_attribute": "height", "relation": "eq", "constant": 1}]}`,
+			ok:     true,
+		},
+		{
+			name:   "without bars section sources are checked",
+			layout: `{"constraints": [{"target": "content", "target_attribute": "ystart", "source": "heder", "source_attribute": "yend", "relation": "eq"}]}`,
+		},
+		{
+			name:   "declared bars",
+			layout: `{"bars": {"Header": {}, "content": {}}, "constraints": [{"target": "content", "target_attribute": "ystart", "source": "header", "source_attribute": "yend", "relation": "eq"}]}`,
+			ok:     true,
+		},
+		{
+			name:   "undeclared target",
+			layout: `{"bars": {"header": {}}, "constraints": [{"target": "contnet", "target_attribute": "height", "relation": "eq", "constant": 1}]}`,
+		},
+		{
+			name:   "undeclared no_overlap bar",
+			layout: `{"bars": {"a": {}, "b": {}}, "constraints": [{"relation": "no_overlap", "bars": ["a", "c"]}]}`,
+		},
+		{
+			name:   "undeclared split bar",
+			layout: `{"bars": {"a": {}}, "splits": [{"name": "s", "first": "a", "second": "b"}]}`,
+		},
+		{
+			name:   "undeclared model bar",
+			layout: `{"bars": {"a": {}}, "models": [{"bar": "b", "name": "m", "text": "m"}]}`,
+		},
+		{
+			name: "bars of fragments are declared",
+			layout: `{
+				"fragments": {"dialog": {"constraints": [{"target": "body", "target_attribute": "ystart", "source": "/header", "source_attribute": "yend", "relation": "eq"}]}},
+				"use": [{"fragment": "dialog", "prefix": "d_"}],
+				"bars": {"header": {}},
+				"constraints": [{"target": "d_body", "target_attribute": "height", "relation": "eq", "constant": 1}]
+			}`,
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewChocolate().FromJson([]byte(tt.layout))
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrUnknownBar) {
+				t.Fatalf("got %v, want %v", err, ErrUnknownBar)
+			}
+		})
+	}
+}
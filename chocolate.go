@@ -1,13 +1,28 @@
 package chocolate
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+var (
+	ErrUnknownBar   = errors.New("unknown bar")
+	ErrUnknownModel = errors.New("unknown model")
+	ErrUnknownVar   = errors.New("unknown variable")
+)
+
+// FallbackView renders the content shown instead of the layout, when the layout
+// can't be resolved for the current size. It gets the current size and the
+// minimum size required by the layout
+type FallbackView func(width, height, minWidth, minHeight int) string
+
 type Chocolate struct {
 	chocolateFlavour
 
@@ -16,6 +31,24 @@ type Chocolate struct {
 
 	root      *constraintLayout
 	rootModel *chocolateBar
+
+	fallback FallbackView
+
+	splitKeys    *SplitKeyMap
+	focusedSplit string
+	drag         *splitDrag
+
+	watches map[string]*layoutWatch
+	// document is the last loaded layout document
+	document *layoutDocument
+
+	debug bool
+}
+
+type splitDrag struct {
+	split *layoutSplit
+	start int
+	size  int
 }
 
 func (c *Chocolate) Resize(width, height int) {
@@ -23,6 +56,11 @@ func (c *Chocolate) Resize(width, height int) {
 	for _, o := range c.overlays {
 		o.Resize(width, height)
 	}
+	if width > 0 && height > 0 {
+		// arranging right away queues the changes of the automatically
+		// hidden bars for the next Update
+		c.root.arrange()
+	}
 }
 
 func (c *Chocolate) setDirty()                               { c.root.setDirty() }
@@ -31,6 +69,9 @@ func (c *Chocolate) addBar(name string, child barChild) bool { return c.root.add
 func (c *Chocolate) View() string {
 	overlaysSorted := []*Overlay{}
 	ret := c.rootModel.View()
+	if c.root.failed() {
+		return c.fallbackView()
+	}
 	w := lipgloss.Width(ret)
 	h := lipgloss.Height(ret)
 	for _, o := range c.overlays {
@@ -49,50 +90,406 @@ func (c *Chocolate) View() string {
 		ox, oy := o.calcPosition(w, h, ow, oh)
 		ret = placeOverlay(ox, oy, oview, ret)
 	}
+	if c.debug {
+		ret = c.debugView(ret)
+	}
 	return ret
 }
 
+func (c *Chocolate) fallbackView() string {
+	width := c.rootModel.width()
+	height := c.rootModel.height()
+	minWidth, minHeight := c.root.minSize()
+	fw, fh := c.rootModel.frameSize()
+	minWidth += fw
+	minHeight += fh
+
+	style := lipgloss.NewStyle()
+	if s, ok := c.styles[TS_DEFAULT]; ok {
+		style = *s
+	}
+	var text string
+	if width >= minWidth && height >= minHeight {
+		// the layout fits, so it is broken
+		text = fmt.Sprintf("Layout error\n%v", c.root.layoutError())
+	} else if c.fallback != nil {
+		return c.fallback(width, height, minWidth, minHeight)
+	} else {
+		text = fmt.Sprintf("Terminal too small\ncurrent: %dx%d\nrequired: %dx%d", width, height, minWidth, minHeight)
+	}
+	msg := style.
+		UnsetWidth().
+		UnsetHeight().
+		Render(text)
+
+	return clipBlock(
+		lipgloss.Place(
+			width, height,
+			lipgloss.Center, lipgloss.Center,
+			msg,
+			lipgloss.WithWhitespaceBackground(style.GetBackground()),
+		),
+		width, height,
+	)
+}
+
+// LayoutError returns why the layout couldn't be rendered by the last View.
+// If the required constraints conflict, but the layout fits anyway, the
+// layout is rendered and the conflict is returned as well. The same applies
+// to models, whose minimum size is above their maximum. Explain shows the
+// rejected constraints of a bar
+func (c *Chocolate) LayoutError() error {
+	return c.root.layoutError()
+}
+
+// SetFallbackView replaces the default view, that is shown when the layout can't
+// be resolved. Setting it to nil restores the default view
+func (c *Chocolate) SetFallbackView(fn FallbackView) {
+	c.fallback = fn
+}
+
 func (c *Chocolate) AddConstraints(constraints ...Constraint) {
 	c.root.addConstraints(constraints...)
 	for _, constraint := range constraints {
-		c.MakeBar(constraint.Target, false)
+		for _, name := range constraint.barNames() {
+			c.MakeBar(name, false)
+		}
 	}
 }
 
+// FromFile loads the layout file. Relative includes are resolved
+// against the directory of the file
 func (c *Chocolate) FromFile(file string) error {
-	if layout, err := os.ReadFile(file); err == nil {
-		return c.FromJson(layout)
-	} else {
-		return err
+	_, err := c.fromFile(file)
+	return err
+}
+
+func (c *Chocolate) fromFile(file string) ([]string, error) {
+	doc, files, err := loadLayoutFile(file)
+	if err != nil {
+		return files, err
+	}
+	if err := c.validateDocument(doc); err != nil {
+		return files, err
+	}
+	if err := c.createModels(doc); err != nil {
+		return files, err
 	}
+	if err := c.setDocument(doc); err != nil {
+		return files, err
+	}
+
+	return files, nil
 }
 
+// FromJson loads the layout. Relative includes are resolved
+// against the working directory
 func (c *Chocolate) FromJson(layout []byte) error {
-	if err := c.root.fromJson(layout); err != nil {
+	doc, err := loadLayout(layout, "")
+	if err != nil {
+		return err
+	}
+	if err := c.validateDocument(doc); err != nil {
+		return err
+	}
+	if err := c.createModels(doc); err != nil {
 		return err
 	}
 
+	return c.setDocument(doc)
+}
+
+func (c *Chocolate) setDocument(doc *layoutDocument) error {
+	if c.document != nil {
+		c.removeUndeclared(c.document, doc)
+	}
+	c.document = doc
+	c.drag = nil
+	c.root.setDocument(doc)
+	for _, bar := range doc.Bars {
+		c.MakeBar(bar.name, false)
+	}
+	for _, md := range doc.Models {
+		c.MakeBar(md.Bar, false)
+	}
 	for _, con := range c.root.constraints {
-		c.MakeBar(con.Target, false)
+		for _, name := range con.barNames() {
+			c.MakeBar(name, false)
+		}
+	}
+	return c.applyDocument(doc)
+}
+
+// removeUndeclared removes the bars, models and variables, that the previous
+// document used and the new one doesn't. Padding and gap are reset to their
+// defaults, if the new document doesn't set them anymore
+func (c *Chocolate) removeUndeclared(prev *layoutDocument, doc *layoutDocument) {
+	prevBars, prevModels := prev.names()
+	bars, models := doc.names()
+	declared := map[string][]string{}
+	for _, name := range bars {
+		declared[barKey(name)] = append(declared[barKey(name)], models[name]...)
+	}
+	for _, name := range prevBars {
+		if _, ok := declared[barKey(name)]; !ok {
+			c.removeBar(name)
+		}
+	}
+	for bar, names := range prevModels {
+		b, ok := c.bars[barKey(bar)]
+		if !ok {
+			continue
+		}
+		for _, name := range names {
+			if !slices.Contains(declared[barKey(bar)], name) {
+				b.removeModel(name)
+			}
+		}
+	}
+
+	for _, con := range prev.Constraints {
+		if con.Var != "" && !slices.ContainsFunc(doc.Constraints, func(v Constraint) bool {
+			return strings.EqualFold(v.Var, con.Var)
+		}) {
+			c.root.removeVar(con.Var)
+		}
+	}
+	if len(prev.Padding) > 0 && len(doc.Padding) == 0 {
+		c.SetLayoutPadding(0)
+	}
+	if prev.Gap != nil && doc.Gap == nil {
+		c.SetLayoutGap(0)
+	}
+}
+
+// removeBar removes the bar from the layout
+func (c *Chocolate) removeBar(name string) {
+	name = barKey(name)
+	delete(c.bars, name)
+	c.root.removeBar(name)
+}
+
+func (c *Chocolate) applyDocument(doc *layoutDocument) error {
+	for _, split := range doc.Splits {
+		if err := c.AddSplit(split); err != nil {
+			return err
+		}
+	}
+	if len(doc.Padding) > 0 {
+		c.SetLayoutPadding(doc.Padding...)
+	}
+	if doc.Gap != nil {
+		c.SetLayoutGap(*doc.Gap)
+	}
+	for _, bar := range doc.Bars {
+		if err := c.applyBarDocument(bar.name, bar.barDocument); err != nil {
+			return err
+		}
+	}
+	for _, md := range doc.Models {
+		if err := c.applyModelDocument(md.Bar, md.modelDocument); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (c *Chocolate) AddThemeModifier(name string, model string, style FlavourStyleSelector, modifiers ...ThemeStyleModifier) {
-	if b, ok := c.bars[name]; ok {
-		b.addThemeModifier(model, style, modifiers...)
+// applyBarDocument applies the declared settings and models of the bar
+func (c *Chocolate) applyBarDocument(name string, doc barDocument) error {
+	b, err := c.getBar(name)
+	if err != nil {
+		return err
+	}
+
+	if doc.Z != nil {
+		b.setZIndex(*doc.Z)
+	}
+	if doc.CanHide != nil {
+		b.setCanHide(*doc.CanHide)
+	}
+	if doc.Overflow != nil {
+		b.setOverflow(*doc.Overflow)
 	}
+
+	for _, md := range doc.Models {
+		if err := c.applyModelDocument(name, md); err != nil {
+			return err
+		}
+	}
+
+	if doc.Selected != "" {
+		b.selectModel(doc.Selected)
+	}
+	if doc.Hidden != nil {
+		if *doc.Hidden {
+			b.hide()
+		} else {
+			b.unhide()
+		}
+	}
+
+	return nil
+}
+
+// applyModelDocument adds the declared model to the bar. Declared text
+// models, that exist already, get the text and theme of the document,
+// so a reloaded document replaces the previous declaration
+func (c *Chocolate) applyModelDocument(bar string, md modelDocument) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+
+	name := strings.ToLower(md.Name)
+	text := ""
+	if md.Text != nil {
+		text = *md.Text
+	}
+	var style lipgloss.Style
+	if md.instance == nil && !md.flavoured() {
+		if style, err = md.style(); err != nil {
+			return err
+		}
+	}
+	if model, ok := b.models[name]; !ok {
+		switch {
+		case md.instance != nil:
+			if err := c.AddModelBarModel(md.instance, name, bar, md.flavoured(), md.Styles...); err != nil {
+				return err
+			}
+		case md.flavoured():
+			tm, err := c.MakeText(name, bar, true, md.Styles...)
+			if err != nil {
+				return err
+			}
+			tm.SetText(text)
+		default:
+			tm, err := c.MakeStyledText(name, bar, &style)
+			if err != nil {
+				return err
+			}
+			tm.SetText(text)
+		}
+	} else if tm, ok := model.source().(*TextModel); ok {
+		if md.Text != nil {
+			tm.SetText(text)
+		}
+		if !md.flavoured() {
+			tm.bar.setStyle(style)
+		}
+	}
+	model, ok := b.models[name]
+	if !ok {
+		return fmt.Errorf("%w: '%s' in bar '%s'", ErrUnknownModel, name, bar)
+	}
+
+	if md.flavoured() {
+		for style, theme := range md.Theme {
+			b.setThemeModifiers(name, style, theme.modifiers()...)
+		}
+	}
+	if md.Style != "" {
+		model.selectStyle(md.Style)
+	}
+	if md.Overflow != nil {
+		b.setModelOverflow(name, *md.Overflow)
+	}
+
+	return nil
+}
+
+// getBar returns the bar or an error, if it doesn't exist
+func (c *Chocolate) getBar(name string) (*chocolateBar, error) {
+	b, ok := c.bars[barKey(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownBar, name)
+	}
+	return b, nil
+}
+
+// getModel returns the model of the bar or an error, if the bar or
+// the model doesn't exist
+func (c *Chocolate) getModel(bar string, name string) (chocolateModel, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return nil, err
+	}
+	model, ok := b.models[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' in bar '%s'", ErrUnknownModel, name, bar)
+	}
+	return model, nil
+}
+
+func (c *Chocolate) AddThemeModifier(name string, model string, style FlavourStyleSelector, modifiers ...ThemeStyleModifier) error {
+	m, err := c.getModel(name, model)
+	if err != nil {
+		return err
+	}
+	m.addThemeModifier(style, modifiers...)
+
+	return nil
+}
+
+// SetTitle draws the label into the top border of the model of the bar,
+// when the style is selected for the model. Labels set for TS_DEFAULT are
+// used for styles without an own label
+func (c *Chocolate) SetTitle(bar string, model string, style FlavourStyleSelector, label BorderLabel) error {
+	m, err := c.getModel(bar, model)
+	if err != nil {
+		return err
+	}
+	m.setLabel(style, newBorderLabel(label, &c.chocolateFlavour), false)
+
+	return nil
+}
+
+// SetFooter draws the label into the bottom border of the model of the bar,
+// like SetTitle does for the top border
+func (c *Chocolate) SetFooter(bar string, model string, style FlavourStyleSelector, label BorderLabel) error {
+	m, err := c.getModel(bar, model)
+	if err != nil {
+		return err
+	}
+	m.setLabel(style, newBorderLabel(label, &c.chocolateFlavour), true)
+
+	return nil
+}
+
+// SetBarTitle sets the title for all models of the bar without an own title
+func (c *Chocolate) SetBarTitle(bar string, style FlavourStyleSelector, label BorderLabel) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setLabel(style, newBorderLabel(label, &c.chocolateFlavour), false)
+
+	return nil
+}
+
+// SetBarFooter sets the footer for all models of the bar without an own footer
+func (c *Chocolate) SetBarFooter(bar string, style FlavourStyleSelector, label BorderLabel) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setLabel(style, newBorderLabel(label, &c.chocolateFlavour), true)
+
+	return nil
 }
 
 func (c *Chocolate) AddRootThemeModifier(style FlavourStyleSelector, modifiers ...ThemeStyleModifier) {
 	c.rootModel.addThemeModifier("default", style, modifiers...)
 }
 
-func (c *Chocolate) MakeBar(name string, canhide bool) bool {
-	if _, ok := c.bars[name]; ok {
-		return false
+// MakeBar makes the bar and returns its handle. If the bar exists already,
+// the handle of the existing bar is returned
+func (c *Chocolate) MakeBar(name string, canhide bool) *Bar {
+	if b, err := c.Bar(name); err == nil {
+		return b
 	}
+	name = barKey(name)
 	bar := newChocolateBar(
 		"",
 		nil,
@@ -102,14 +499,21 @@ func (c *Chocolate) MakeBar(name string, canhide bool) bool {
 		c.bars = make(map[string]*chocolateBar)
 	}
 	c.bars[name] = bar
+	c.addBar(name, bar)
 
-	return c.addBar(name, bar)
+	return &Bar{
+		c:    c,
+		name: name,
+		bar:  bar,
+	}
 }
 
-func (c *Chocolate) MakeChocolate(name string, bar string, flavoured bool, styles ...FlavourStyleSelector) *Chocolate {
-	b, ok := c.bars[bar]
-	if !ok {
-		return nil
+// MakeChocolate places a new chocolate with the name under the bar and
+// returns it to define its layout
+func (c *Chocolate) MakeChocolate(name string, bar string, flavoured bool, styles ...FlavourStyleSelector) (*Chocolate, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return nil, err
 	}
 	var model *chocolateBarModel[*Chocolate]
 	if flavoured {
@@ -120,9 +524,11 @@ func (c *Chocolate) MakeChocolate(name string, bar string, flavoured bool, style
 	b.addModel(name, model)
 	// b.SelectModel(name)
 
-	return model.model()
+	return model.model(), nil
 }
 
+// MakeOverlay makes the overlay with the name. If the overlay exists
+// already, the existing overlay is returned, so it's never nil
 func (c *Chocolate) MakeOverlay(
 	name string,
 	zindex int,
@@ -148,10 +554,10 @@ func (c *Chocolate) MakeOverlay(
 	return o
 }
 
-func (c *Chocolate) MakeText(name string, bar string, flavoured bool, styles ...FlavourStyleSelector) *TextModel {
-	b, ok := c.bars[bar]
-	if !ok {
-		return nil
+func (c *Chocolate) MakeText(name string, bar string, flavoured bool, styles ...FlavourStyleSelector) (*TextModel, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return nil, err
 	}
 	var model *chocolateBarModel[*TextModel]
 	if flavoured {
@@ -162,25 +568,41 @@ func (c *Chocolate) MakeText(name string, bar string, flavoured bool, styles ...
 	b.addModel(name, model)
 	// b.SelectModel(name)
 
-	return model.model()
+	return model.model(), nil
 }
 
-func (c *Chocolate) MakeStyledText(name string, bar string, style *lipgloss.Style) *TextModel {
-	b, ok := c.bars[bar]
-	if !ok {
-		return nil
+func (c *Chocolate) MakeStyledText(name string, bar string, style *lipgloss.Style) (*TextModel, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return nil, err
 	}
 	model := newStyledTextBarModel("", style)
 	b.addModel(name, model)
 	// b.SelectModel(name)
 
-	return model.model()
+	return model.model(), nil
 }
 
-func (c *Chocolate) AddViewBarModel(model BarViewer, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) {
-	b, ok := c.bars[bar]
-	if !ok {
-		return
+// MakeScroll wraps the viewer into a ScrollModel and places it with the name under the bar.
+// When flavoured is set the scrollbar will use the flavour of the chocolate
+func (c *Chocolate) MakeScroll(viewer BarViewer, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) (*ScrollModel, error) {
+	var model *ScrollModel
+	if flavoured {
+		model = NewScrollModel(viewer, WithFlavouredScrollbar(&c.chocolateFlavour))
+	} else {
+		model = NewScrollModel(viewer)
+	}
+	if err := c.AddModelBarModel(model, name, bar, flavoured, styles...); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (c *Chocolate) AddViewBarModel(model BarViewer, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
 	var _model *chocolateBarModel[BarViewer]
 	if flavoured {
@@ -189,23 +611,25 @@ func (c *Chocolate) AddViewBarModel(model BarViewer, name string, bar string, fl
 		_model = newViewBarModel(model)
 	}
 	b.addModel(name, _model)
-	// b.SelectModel(name)
+
+	return nil
 }
 
-func (c *Chocolate) AddStyledViewBarModel(model BarViewer, name string, bar string, style *lipgloss.Style) {
-	b, ok := c.bars[bar]
-	if !ok {
-		return
+func (c *Chocolate) AddStyledViewBarModel(model BarViewer, name string, bar string, style *lipgloss.Style) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
 	_model := newStyledViewBarModel(model, style)
 	b.addModel(name, _model)
-	// b.SelectModel(name)
+
+	return nil
 }
 
-func (c *Chocolate) AddModelBarModel(model BarModel, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) {
-	b, ok := c.bars[bar]
-	if !ok {
-		return
+func (c *Chocolate) AddModelBarModel(model BarModel, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
 	var _model *chocolateBarModel[BarModel]
 	if flavoured {
@@ -214,13 +638,14 @@ func (c *Chocolate) AddModelBarModel(model BarModel, name string, bar string, fl
 		_model = newModelBarModel(model)
 	}
 	b.addModel(name, _model)
-	// b.SelectModel(name)
+
+	return nil
 }
 
-func (c *Chocolate) AddTeaModelBarModel(model tea.Model, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) {
-	b, ok := c.bars[bar]
-	if !ok {
-		return
+func (c *Chocolate) AddTeaModelBarModel(model tea.Model, name string, bar string, flavoured bool, styles ...FlavourStyleSelector) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
 	_model := newTeaModel(model)
 	if flavoured {
@@ -230,55 +655,364 @@ func (c *Chocolate) AddTeaModelBarModel(model tea.Model, name string, bar string
 		_bar := newModelBarModel(_model)
 		b.addModel(name, _bar)
 	}
-	// b.SelectModel(name)
+
+	return nil
 }
 
-func (c *Chocolate) SelectModel(name string, bar string) {
-	if b, ok := c.bars[bar]; ok {
-		b.selectModel(name)
+func (c *Chocolate) SelectModel(name string, bar string) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	if !b.selectModel(name) {
+		return fmt.Errorf("%w: '%s' in bar '%s'", ErrUnknownModel, name, bar)
 	}
+
+	return nil
 }
 
-func (c *Chocolate) SelectStyle(name FlavourStyleSelector, bar string) {
-	if b, ok := c.bars[bar]; ok {
-		b.selectStyle(name)
+func (c *Chocolate) SelectStyle(name FlavourStyleSelector, bar string) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
+	b.selectStyle(name)
+
+	return nil
 }
 
 func (c *Chocolate) SelectRootStyle(name FlavourStyleSelector) {
 	c.rootModel.selectStyle(name)
 }
 
-func (c *Chocolate) SetCanHide(bar string, v bool) {
-	if b, ok := c.bars[bar]; ok {
-		b.setCanHide(v)
+// SetOverflow sets the overflow policy for all models of the bar
+// that don't have an own policy set by SetModelOverflow
+func (c *Chocolate) SetOverflow(bar string, policy OverflowPolicy) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
 	}
+	b.setOverflow(policy)
+
+	return nil
+}
+
+// SetModelOverflow sets the overflow policy for a single model of the bar
+func (c *Chocolate) SetModelOverflow(bar string, model string, policy OverflowPolicy) error {
+	m, err := c.getModel(bar, model)
+	if err != nil {
+		return err
+	}
+	m.setOverflow(policy, true)
+
+	return nil
+}
+
+func (c *Chocolate) SetCanHide(bar string, v bool) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setCanHide(v)
+
+	return nil
+}
+
+// SetHidePriority sets the priority used to hide bars automatically
+// when the layout doesn't fit. Bars with the lowest priority are hidden first.
+// Only bars that can hide are taken into account
+func (c *Chocolate) SetHidePriority(bar string, priority int) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setHidePriority(priority)
+
+	return nil
+}
+
+// SetMinSize sets the minimum size of the bar. If the layout can't
+// provide it other bars will be hidden automatically
+func (c *Chocolate) SetMinSize(bar string, width, height int) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setMinSize(width, height)
+
+	return nil
+}
+
+// OnAutoHide registers a callback that is called whenever bars are hidden or
+// shown again automatically. The callback may be called while rendering.
+// The same changes are returned as AutoHideMsg by Update
+func (c *Chocolate) OnAutoHide(fn func(AutoHideMsg)) {
+	c.root.onAutoHide = fn
 }
 
-func (c *Chocolate) IsHidden(bar string) bool {
-	if b, ok := c.bars[bar]; ok {
-		return b.isHidden()
+// autoHideCmd returns the queued changes of the automatically hidden
+// bars of the chocolate and the nested ones
+func (c *Chocolate) autoHideCmd() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for _, msg := range c.root.autoHides {
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	c.root.autoHides = nil
+	for _, nested := range c.nested() {
+		cmds = append(cmds, nested.autoHideCmd())
 	}
-	return true // fake hidden if not existing
+
+	return tea.Batch(cmds...)
 }
 
-func (c *Chocolate) Hide(bar string) {
-	if b, ok := c.bars[bar]; ok {
-		b.hide()
+func (c *Chocolate) IsAutoHidden(bar string) (bool, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return false, err
 	}
+	return b.isAutoHidden(), nil
 }
 
-func (c *Chocolate) Unhide(bar string) {
-	if b, ok := c.bars[bar]; ok {
-		b.unhide()
+func (c *Chocolate) IsHidden(bar string) (bool, error) {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return false, err
 	}
+	return b.isHidden(), nil
+}
+
+func (c *Chocolate) Hide(bar string) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.hide()
+
+	return nil
+}
+
+func (c *Chocolate) Unhide(bar string) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.unhide()
+
+	return nil
+}
+
+// SetZIndex sets the z index of the bar. Bars with a higher index are painted
+// over bars with a lower one. Bars with the same index are painted in the
+// order they were declared
+func (c *Chocolate) SetZIndex(bar string, z int) error {
+	b, err := c.getBar(bar)
+	if err != nil {
+		return err
+	}
+	b.setZIndex(z)
+
+	return nil
+}
+
+// SetLayoutPadding sets the insets of the layout. The values are used in the
+// same order as lipgloss does for paddings. References to super are relative
+// to the area inside the insets
+func (c *Chocolate) SetLayoutPadding(i ...int) {
+	c.root.setPadding(i...)
+}
+
+// SetLayoutGap sets the gap between bars, that are placed next to each other
+// by constraints (ae.: xstart of one bar equals xend of another one) or
+// by a no_overlap relation
+func (c *Chocolate) SetLayoutGap(v int) {
+	c.root.setGap(v)
+}
+
+// SetBorderCollapse enables the collapsed border mode. The borders of the bars
+// are drawn by the layout along the resolved rectangles using the border style
+// of the flavour. Borders of bars next to each other are merged.
+// The own borders of the models inside the bars are not rendered in this mode,
+// their titles and footers are drawn into the merged borders instead
+func (c *Chocolate) SetBorderCollapse(v bool) {
+	if v {
+		c.root.setCollapsed(newCollapsedBorder(&c.chocolateFlavour))
+	} else {
+		c.root.setCollapsed(nil)
+	}
+}
+
+// BarAt returns the name of the topmost bar at the position relative to the
+// chocolate's origin
+func (c *Chocolate) BarAt(x, y int) (string, bool) {
+	ox, oy := c.rootModel.contentOffset()
+	return c.root.barAt(x-ox, y-oy)
+}
+
+// AddSplit places the bars of the split next to each other and shares
+// the space between them by the ratio of the split. The ratio can be changed
+// with AdjustSplit, by dragging the boundary with the mouse or by the
+// keybindings, when the split is focused
+func (c *Chocolate) AddSplit(split Split) error {
+	if err := c.root.addSplit(split); err != nil {
+		return err
+	}
+	for _, name := range []string{split.First, split.Second} {
+		if !c.IsBar(name) {
+			c.MakeBar(name, false)
+		}
+	}
+
+	return nil
+}
+
+// AdjustSplit moves the boundary of the split by delta cells.
+// It returns false, if nothing has changed
+func (c *Chocolate) AdjustSplit(name string, delta int) bool {
+	return c.root.adjustSplit(name, delta)
+}
+
+// SetSplitRatio sets the share of the first bar of the split.
+// The layout is resolved again by the next View
+func (c *Chocolate) SetSplitRatio(name string, ratio float64) bool {
+	return c.root.setSplitRatio(name, ratio)
+}
+
+// SplitRatio returns the current share of the first bar of the split
+func (c *Chocolate) SplitRatio(name string) (float64, bool) {
+	if s := c.root.split(name); s != nil {
+		return s.ratio, true
+	}
+	return 0, false
+}
+
+// FocusSplit selects the split, that is resized by the keybindings.
+// The keys are left to the other models, while no split is focused
+func (c *Chocolate) FocusSplit(name string) error {
+	name = strings.ToLower(name)
+	if c.root.split(name) == nil {
+		return fmt.Errorf("unknown split: '%s'", name)
+	}
+	c.focusedSplit = name
+	return nil
+}
+
+// BlurSplit clears the focus of the split, so the keybindings
+// aren't handled anymore
+func (c *Chocolate) BlurSplit() {
+	c.focusedSplit = ""
+}
+
+// FocusedSplit returns the name of the focused split
+func (c *Chocolate) FocusedSplit() (string, bool) {
+	return c.focusedSplit, c.focusedSplit != ""
+}
+
+// splitKeysActive tells if the keybindings resize the focused split.
+// This is only the case while both bars of the split are shown
+func (c *Chocolate) splitKeysActive() bool {
+	if c.focusedSplit == "" || c.splitKeys == nil {
+		return false
+	}
+	s := c.root.split(c.focusedSplit)
+	if s == nil {
+		return false
+	}
+	first, fok := c.root.children[s.First]
+	second, sok := c.root.children[s.Second]
+
+	return fok && sok && !first.isHidden() && !second.isHidden()
+}
+
+func (c *Chocolate) SetSplitKeyMap(km *SplitKeyMap) {
+	c.splitKeys = km
+}
+
+// Update handles the resizing of the splits by mouse and keyboard
+// and the reloading of watched layout files.
+// Mouse positions are relative to the chocolate's origin.
+// Bars that were hidden or shown again automatically since the last call,
+// for example because of a Resize, are reported as AutoHideMsg
+func (c *Chocolate) Update(msg tea.Msg) tea.Cmd {
+	return tea.Batch(c.update(msg), c.autoHideCmd())
+}
+
+func (c *Chocolate) update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case layoutPollMsg:
+		return c.reload(msg)
+	case tea.KeyMsg:
+		if !c.splitKeysActive() {
+			break
+		}
+		switch {
+		case key.Matches(msg, c.splitKeys.Grow):
+			c.AdjustSplit(c.focusedSplit, 1)
+		case key.Matches(msg, c.splitKeys.Shrink):
+			c.AdjustSplit(c.focusedSplit, -1)
+		}
+	case tea.MouseMsg:
+		ox, oy := c.rootModel.contentOffset()
+		x, y := msg.X-ox, msg.Y-oy
+		switch msg.Action {
+		case tea.MouseActionPress:
+			if msg.Button != tea.MouseButtonLeft {
+				break
+			}
+			s, ok := c.root.splitAt(x, y)
+			if !ok {
+				break
+			}
+			size, _, _ := c.root.splitSizes(s)
+			c.drag = &splitDrag{split: s, start: x, size: size}
+			if s.vertical() {
+				c.drag.start = y
+			}
+		case tea.MouseActionMotion:
+			if c.drag == nil {
+				break
+			}
+			pos := x
+			if c.drag.split.vertical() {
+				pos = y
+			}
+			c.root.resizeSplit(c.drag.split, c.drag.size+pos-c.drag.start)
+		case tea.MouseActionRelease:
+			c.drag = nil
+		}
+	}
+
+	return nil
+}
+
+// SetVar sets the value of the layout variable, that constraints reference
+// by their Var. Changing a variable doesn't require to resolve the whole
+// layout again in most cases. The value is kept against all constraints,
+// that aren't required
+func (c *Chocolate) SetVar(name string, value float64) error {
+	if !c.root.setVar(name, value) {
+		return fmt.Errorf("%w: '%s'", ErrUnknownVar, name)
+	}
+	return nil
+}
+
+// Var returns the value of the layout variable
+func (c *Chocolate) Var(name string) (float64, bool) {
+	return c.root.getVar(name)
 }
 
 func (c *Chocolate) IsBar(bar string) bool {
-	_, ok := c.bars[bar]
+	_, ok := c.bars[barKey(bar)]
 	return ok
 }
 
+// AutoHideMsg reports the bars that were hidden or shown again
+// automatically because of the available space. It is returned by
+// Chocolate.Update and passed to the OnAutoHide callback
+type AutoHideMsg struct {
+	Hidden   []string
+	Unhidden []string
+}
+
 type ChocolateOption func(*Chocolate)
 
 func WithFlavour(flavour *chocolateFlavour) ChocolateOption {
@@ -291,6 +1025,7 @@ func NewChocolate(opts ...ChocolateOption) *Chocolate {
 	ret := &Chocolate{
 		chocolateFlavour: *NewChocolateFlavour(),
 		root:             newConstraintLayout(),
+		splitKeys:        DefaultSplitKeyMap(),
 	}
 
 	for _, opt := range opts {
@@ -1,8 +1,12 @@
 package chocolate
 
 import (
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+
 	"github.com/lithdew/casso"
 )
 
@@ -10,7 +14,16 @@ type chocolateModel interface {
 	barConstrainer
 	barRenderer
 	selectStyle(FlavourStyleSelector)
+	selectedStyle() FlavourStyleSelector
+	currentStyle() lipgloss.Style
+	source() any
 	addThemeModifier(FlavourStyleSelector, ...ThemeStyleModifier)
+	setThemeModifiers(FlavourStyleSelector, ...ThemeStyleModifier)
+	setOverflow(OverflowPolicy, bool)
+	setBorderless(bool)
+	setLabel(FlavourStyleSelector, *borderLabel, bool)
+	frameSize() (int, int)
+	contentOffset() (int, int)
 	setBar(*chocolateBar)
 }
 
@@ -22,7 +35,16 @@ type chocolateBar struct {
 	_xend   int
 	_yend   int
 
-	canhide bool
+	canhide    bool
+	autoHidden bool
+	priority   int
+	minWidth   int
+	minHeight  int
+	z          int
+	overflow   OverflowPolicy
+	inset      int
+
+	labels map[FlavourStyleSelector]*borderLabels
 
 	cElem constraintElement
 
@@ -63,7 +85,7 @@ func (cb *chocolateBar) Resize(width, height int) {
 	cb._height = height
 	cb._xend = cb._xpos + cb._width
 	cb._yend = cb._ypos + cb._height
-	cb.current.setSize(cb._width, cb._height)
+	cb.current.setSize(max(cb._width-cb.inset, 0), max(cb._height-cb.inset, 0))
 }
 
 func (cb *chocolateBar) width() int                    { return cb._width }
@@ -81,20 +103,24 @@ func (cb *chocolateBar) setDirty() {
 	}
 }
 
-func (cb *chocolateBar) getInitConstraints() []casso.Constraint {
+func (cb *chocolateBar) getInitConstraints() []prioritizedConstraint {
 	if cb.current == nil {
 		return nil
 	}
-	ret := []casso.Constraint{}
+	ret := []prioritizedConstraint{}
 
+	inset := 0.0
+	if !cb.isHidden() {
+		inset = float64(cb.inset)
+	}
 	wcon, hcon := cb.current.sizeConstraints()
 	for _, i := range wcon {
-		c := casso.NewConstraint(casso.Op(i.Relation), i.Value, cb.cElem.width.T(1))
-		ret = append(ret, c)
+		i.Value -= inset
+		ret = append(ret, i.constraint(cb.cElem.width))
 	}
 	for _, i := range hcon {
-		c := casso.NewConstraint(casso.Op(i.Relation), i.Value, cb.cElem.height.T(1))
-		ret = append(ret, c)
+		i.Value -= inset
+		ret = append(ret, i.constraint(cb.cElem.height))
 	}
 
 	return ret
@@ -108,6 +134,64 @@ func (cb *chocolateBar) addModel(name string, model chocolateModel) {
 
 	cb.models[strings.ToLower(name)] = model
 	model.setBar(cb)
+	model.setOverflow(cb.overflow, false)
+	model.setBorderless(cb.inset > 0)
+}
+
+// removeModel removes the model from the bar. If it was selected,
+// the first of the remaining models is selected instead
+func (cb *chocolateBar) removeModel(name string) {
+	name = strings.ToLower(name)
+	model, ok := cb.models[name]
+	if !ok {
+		return
+	}
+	delete(cb.models, name)
+	if cb.selected != model {
+		return
+	}
+
+	cb.selected = nil
+	if names := slices.Sorted(maps.Keys(cb.models)); len(names) > 0 {
+		cb.selected = cb.models[names[0]]
+	}
+	if cb.current == model {
+		cb.current = cb.selected
+	}
+	cb.setDirty()
+}
+
+// setBorderInset reserves space on the top and left side of the bar
+// for the borders drawn by the layout
+func (cb *chocolateBar) setBorderInset(v int) {
+	if cb.inset == v {
+		return
+	}
+	cb.inset = v
+	for _, model := range cb.models {
+		model.setBorderless(v > 0)
+	}
+	cb.setDirty()
+}
+
+func (cb *chocolateBar) borderInset() int { return cb.inset }
+
+func (cb *chocolateBar) setLabel(style FlavourStyleSelector, label *borderLabel, footer bool) {
+	cb.labels = setLabel(cb.labels, style, label, footer)
+	cb.setDirty()
+}
+
+func (cb *chocolateBar) setOverflow(v OverflowPolicy) {
+	cb.overflow = v
+	for _, model := range cb.models {
+		model.setOverflow(v, false)
+	}
+}
+
+func (cb *chocolateBar) setModelOverflow(name string, v OverflowPolicy) {
+	if model, ok := cb.models[strings.ToLower(name)]; ok {
+		model.setOverflow(v, true)
+	}
 }
 
 func (cb *chocolateBar) hide() {
@@ -118,13 +202,31 @@ func (cb *chocolateBar) hide() {
 		cb.hidden = newHiddenModel()
 	}
 
+	cb.autoHidden = false
 	if cb.current != cb.hidden {
 		cb.setDirty()
 		cb.current = cb.hidden
 	}
 }
 
+func (cb *chocolateBar) autoHide() bool {
+	if !cb.canhide || cb.isHidden() {
+		return false
+	}
+	cb.hide()
+	cb.autoHidden = true
+
+	return true
+}
+
+func (cb *chocolateBar) autoUnhide() {
+	if cb.autoHidden {
+		cb.unhide()
+	}
+}
+
 func (cb *chocolateBar) unhide() {
+	cb.autoHidden = false
 	if cb.current != cb.selected {
 		cb.setDirty()
 		cb.current = cb.selected
@@ -144,6 +246,12 @@ func (cb *chocolateBar) addThemeModifier(name string, style FlavourStyleSelector
 	}
 }
 
+func (cb *chocolateBar) setThemeModifiers(name string, style FlavourStyleSelector, modifiers ...ThemeStyleModifier) {
+	if model, ok := cb.models[strings.ToLower(name)]; ok {
+		model.setThemeModifiers(style, modifiers...)
+	}
+}
+
 func (cb *chocolateBar) View() string {
 	if cb.current == nil {
 		return ""
@@ -151,8 +259,34 @@ func (cb *chocolateBar) View() string {
 	return cb.current.render()
 }
 
-func (cb *chocolateBar) setCanHide(v bool) { cb.canhide = v }
-func (cb *chocolateBar) isHidden() bool    { return cb.hidden == cb.current && cb.hidden != nil }
+func (cb *chocolateBar) frameSize() (int, int) {
+	if cb.current == nil {
+		return 0, 0
+	}
+	return cb.current.frameSize()
+}
+
+func (cb *chocolateBar) contentOffset() (int, int) {
+	if cb.current == nil {
+		return 0, 0
+	}
+	return cb.current.contentOffset()
+}
+
+func (cb *chocolateBar) setCanHide(v bool)     { cb.canhide = v }
+func (cb *chocolateBar) canHide() bool         { return cb.canhide }
+func (cb *chocolateBar) isHidden() bool        { return cb.hidden == cb.current && cb.hidden != nil }
+func (cb *chocolateBar) isAutoHidden() bool    { return cb.autoHidden && cb.isHidden() }
+func (cb *chocolateBar) setHidePriority(v int) { cb.priority = v }
+func (cb *chocolateBar) hidePriority() int     { return cb.priority }
+func (cb *chocolateBar) setMinSize(width, height int) {
+	cb.minWidth = width
+	cb.minHeight = height
+	cb.setDirty()
+}
+func (cb *chocolateBar) zindex() int         { return cb.z }
+func (cb *chocolateBar) setZIndex(v int)     { cb.z = v; cb.setDirty() }
+func (cb *chocolateBar) minSize() (int, int) { return cb.minWidth, cb.minHeight }
 
 func (cb *chocolateBar) selectModel(name string) bool {
 	if model, ok := cb.models[strings.ToLower(name)]; !ok {
@@ -167,6 +301,16 @@ func (cb *chocolateBar) selectModel(name string) bool {
 	}
 }
 
+// selectedName returns the name of the selected model
+func (cb *chocolateBar) selectedName() string {
+	for name, model := range cb.models {
+		if model == cb.selected {
+			return name
+		}
+	}
+	return ""
+}
+
 func (cb *chocolateBar) sizeConstraints() (width, height []barSizeConstraint) {
 	if cb.current == nil {
 		return nil, nil
@@ -174,6 +318,21 @@ func (cb *chocolateBar) sizeConstraints() (width, height []barSizeConstraint) {
 	return cb.current.sizeConstraints()
 }
 
+func (cb *chocolateBar) borderLabels() (title *borderLabel, footer *borderLabel) {
+	if l, ok := cb.current.(labeler); ok {
+		return l.borderLabels()
+	}
+	return nil, nil
+}
+
+// hintError returns the conflict of the size hints of the current model
+func (cb *chocolateBar) hintError() error {
+	if c, ok := cb.current.(hintChecker); ok {
+		return c.hintError()
+	}
+	return nil
+}
+
 func (cb *chocolateBar) constraintTarget(a ConstraintAttribute) bool {
 	if cb.current == nil {
 		return false
@@ -0,0 +1,201 @@
+// Command chocolate-preview renders a layout file with placeholders showing
+// the name, position and size of every bar.
+//
+// With one or more -size flags the layout is printed for each size:
+//
+//	chocolate-preview -size 80x24 -size 200x60 layout.cnf
+//
+// Without -size the preview runs interactively, follows the size of the
+// terminal and reloads the layout file whenever it changes. d toggles the
+// debug outlines of the bars.
+//
+// With -dot the constraint graph of the layout is written in the Graphviz
+// DOT format instead:
+//
+//	chocolate-preview -dot layout.cnf | dot -Tsvg > layout.svg
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mfulz/chocolate"
+)
+
+const placeholderName = "preview"
+
+// sizeFlags collects the values of the repeatable -size flag
+type sizeFlags [][2]int
+
+func (sf *sizeFlags) String() string {
+	ret := make([]string, len(*sf))
+	for i, s := range *sf {
+		ret[i] = fmt.Sprintf("%dx%d", s[0], s[1])
+	}
+	return strings.Join(ret, ",")
+}
+
+func (sf *sizeFlags) Set(v string) error {
+	w, h, ok := strings.Cut(strings.ToLower(v), "x")
+	if !ok {
+		return fmt.Errorf("size has to be WIDTHxHEIGHT")
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil {
+		return err
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil {
+		return err
+	}
+	*sf = append(*sf, [2]int{width, height})
+
+	return nil
+}
+
+// placeholder shows the name and the resolved rectangle of its bar
+type placeholder struct {
+	bar *chocolate.Bar
+}
+
+func (p *placeholder) Resize(width, height int) {}
+func (p *placeholder) View() string {
+	x, y, w, h := p.bar.Rect()
+	return fmt.Sprintf("%s\n%dx%d\n@%d,%d", p.bar.Name(), w, h, x, y)
+}
+
+// fill adds a placeholder to every bar, that doesn't have one yet,
+// and shows it instead of the declared models unless keep is set
+func fill(c *chocolate.Chocolate, keep bool) error {
+	for _, bar := range c.Bars() {
+		if _, ok := chocolate.GetModel[*placeholder](c, bar.Name(), placeholderName); !ok {
+			if err := bar.AddModel(&placeholder{bar: bar}, placeholderName, true); err != nil {
+				return err
+			}
+			if err := bar.AddThemeModifier(placeholderName, chocolate.TS_DEFAULT,
+				chocolate.Border(lipgloss.RoundedBorder()),
+				chocolate.Align(lipgloss.Center, lipgloss.Center),
+			); err != nil {
+				return err
+			}
+		}
+		if !keep || bar.Selected() == "" {
+			if err := bar.Select(placeholderName); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type model struct {
+	choc   *chocolate.Chocolate
+	file   string
+	keep   bool
+	err    error
+	width  int
+	height int
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.choc.WatchFile(m.file, 500*time.Millisecond)
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.choc.Resize(msg.Width, msg.Height)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "d":
+			m.choc.ToggleDebug()
+			return m, nil
+		}
+	case chocolate.LayoutReloadedMsg:
+		m.err = msg.Err
+		if m.err == nil {
+			m.err = fill(m.choc, m.keep)
+			m.choc.Resize(m.width, m.height)
+		}
+		return m, nil
+	}
+
+	return m, m.choc.Update(msg)
+}
+
+func (m *model) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("%s: %v\n\nthe previous layout is kept until the file is fixed", m.file, m.err)
+	}
+	return m.choc.View()
+}
+
+func main() {
+	var sizes sizeFlags
+	flag.Var(&sizes, "size", "render the layout at WIDTHxHEIGHT and exit (repeatable)")
+	keep := flag.Bool("keep-models", false, "show the models declared in the layout instead of placeholders")
+	debug := flag.Bool("debug", false, "outline the bars with their names, sizes and positions")
+	dot := flag.Bool("dot", false, "write the constraint graph in the Graphviz DOT format and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] layout.cnf\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	file := flag.Arg(0)
+
+	choc := chocolate.NewChocolate()
+	if err := choc.FromFile(file); err != nil {
+		fmt.Fprintf(os.Stderr, "chocolate-preview: %v\n", err)
+		os.Exit(1)
+	}
+	if *dot {
+		if err := choc.ExportDOT(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "chocolate-preview: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := fill(choc, *keep); err != nil {
+		fmt.Fprintf(os.Stderr, "chocolate-preview: %v\n", err)
+		os.Exit(1)
+	}
+	choc.SetDebug(*debug)
+
+	if len(sizes) > 0 {
+		for i, s := range sizes {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%dx%d\n", s[0], s[1])
+			choc.Resize(s[0], s[1])
+			fmt.Println(choc.View())
+		}
+		return
+	}
+
+	m := &model{
+		choc: choc,
+		file: file,
+		keep: *keep,
+	}
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "chocolate-preview: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,394 @@
+// Command chocolategen generates Go constants for the bar and model names
+// of a layout file, so renaming a bar in the layout breaks the build instead
+// of the UI.
+//
+// Usage with go generate:
+//
+//	//go:generate go run github.com/mfulz/chocolate/cmd/chocolategen -layout layout.cnf
+//
+// -layout can be given multiple times for programs using more than one
+// layout file. The names of all files are merged.
+//
+// With -check the Go files of the given directories are type checked and
+// scanned for string literals passed as bar name to the functions and the
+// Chocolate methods of chocolate. The command fails for every name, that
+// is not used by the layout:
+//
+//	chocolategen -layout layout.cnf -check ./...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mfulz/chocolate"
+)
+
+// chocolatePkg is the import path of chocolate
+const chocolatePkg = "github.com/mfulz/chocolate"
+
+// barArgs maps the functions and methods of chocolate, that take a bar name,
+// to the position of the bar argument. Methods are given with the name of
+// their receiver type
+var barArgs = map[string]int{
+	"Chocolate.AddModelBarModel":      2,
+	"Chocolate.AddStyledViewBarModel": 2,
+	"Chocolate.AddTeaModelBarModel":   2,
+	"Chocolate.AddThemeModifier":      0,
+	"Chocolate.AddViewBarModel":       2,
+	"Chocolate.Bar":                   0,
+	"Chocolate.Explain":               0,
+	"Chocolate.Hide":                  0,
+	"Chocolate.IsAutoHidden":          0,
+	"Chocolate.IsBar":                 0,
+	"Chocolate.IsHidden":              0,
+	"Chocolate.MakeBar":               0,
+	"Chocolate.MakeChocolate":         1,
+	"Chocolate.MakeScroll":            2,
+	"Chocolate.MakeStyledText":        1,
+	"Chocolate.MakeText":              1,
+	"Chocolate.SelectModel":           1,
+	"Chocolate.SelectStyle":           1,
+	"Chocolate.SetBarFooter":          0,
+	"Chocolate.SetBarTitle":           0,
+	"Chocolate.SetCanHide":            0,
+	"Chocolate.SetFooter":             0,
+	"Chocolate.SetHidePriority":       0,
+	"Chocolate.SetMinSize":            0,
+	"Chocolate.SetModelOverflow":      0,
+	"Chocolate.SetOverflow":           0,
+	"Chocolate.SetTitle":              0,
+	"Chocolate.SetZIndex":             0,
+	"Chocolate.Unhide":                0,
+	"GetModel":                        1,
+}
+
+// layoutFlags collects the values of the repeatable -layout flag
+type layoutFlags []string
+
+func (lf *layoutFlags) String() string { return strings.Join(*lf, ",") }
+func (lf *layoutFlags) Set(v string) error {
+	*lf = append(*lf, v)
+	return nil
+}
+
+func main() {
+	var layouts layoutFlags
+	flag.Var(&layouts, "layout", "layout file to read (repeatable)")
+	out := flag.String("out", "", "output file (default <first layout>_names.go, - for stdout)")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated file (default $GOPACKAGE or main)")
+	barPrefix := flag.String("bar-prefix", "Bar", "prefix of the bar constants")
+	modelPrefix := flag.String("model-prefix", "Model", "prefix of the model constants")
+	check := flag.Bool("check", false, "check the bar names used in the Go files of the directories")
+	flag.Parse()
+
+	if len(layouts) == 0 {
+		fmt.Fprintln(os.Stderr, "chocolategen: -layout is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	bars, models, err := layoutNames(layouts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chocolategen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *check {
+		dirs := flag.Args()
+		if len(dirs) == 0 {
+			dirs = []string{"."}
+		}
+		problems, err := checkDirs(dirs, bars)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chocolategen: %v\n", err)
+			os.Exit(1)
+		}
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, p)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *pkg == "" {
+		*pkg = "main"
+	}
+	src, err := generate(layouts, *pkg, *barPrefix, *modelPrefix, bars, models)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "chocolategen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "-" {
+		os.Stdout.Write(src)
+		return
+	}
+	if *out == "" {
+		base := strings.TrimSuffix(filepath.Base(layouts[0]), filepath.Ext(layouts[0]))
+		*out = filepath.Join(filepath.Dir(layouts[0]), base+"_names.go")
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "chocolategen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// layoutNames merges the names of the layout files
+func layoutNames(layouts []string) ([]string, map[string][]string, error) {
+	bars := []string{}
+	models := map[string][]string{}
+	for _, layout := range layouts {
+		b, m, err := chocolate.LayoutNames(layout)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, bar := range b {
+			if !slices.Contains(bars, bar) {
+				bars = append(bars, bar)
+			}
+		}
+		for bar, names := range m {
+			for _, name := range names {
+				if !slices.Contains(models[bar], name) {
+					models[bar] = append(models[bar], name)
+				}
+			}
+		}
+	}
+
+	return bars, models, nil
+}
+
+func generate(layouts []string, pkg, barPrefix, modelPrefix string, bars []string, models map[string][]string) ([]byte, error) {
+	var b bytes.Buffer
+	names := make([]string, len(layouts))
+	for i, layout := range layouts {
+		names[i] = filepath.Base(layout)
+	}
+	name := strings.Join(names, ", ")
+
+	fmt.Fprintf(&b, "// Code generated by chocolategen from %s. DO NOT EDIT.\n\n", name)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	// names, that only differ by the separators, make the same identifier
+	idents := map[string]string{}
+	constant := func(ident, name string) error {
+		if other, ok := idents[ident]; ok {
+			return fmt.Errorf("'%s' and '%s' both generate the constant %s", other, name, ident)
+		}
+		idents[ident] = name
+		fmt.Fprintf(&b, "\t%s = %q\n", ident, name)
+		return nil
+	}
+
+	fmt.Fprintf(&b, "// Bars of %s\n", name)
+	b.WriteString("const (\n")
+	for _, bar := range bars {
+		if err := constant(identifier(barPrefix, bar), bar); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString(")\n")
+
+	if len(models) > 0 {
+		owners := make([]string, 0, len(models))
+		for bar := range models {
+			owners = append(owners, bar)
+		}
+		sort.Strings(owners)
+
+		fmt.Fprintf(&b, "\n// Models declared in %s\n", name)
+		b.WriteString("const (\n")
+		for _, bar := range owners {
+			for _, model := range models[bar] {
+				if err := constant(identifier(modelPrefix+identifier("", bar), model), model); err != nil {
+					return nil, err
+				}
+			}
+		}
+		b.WriteString(")\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// identifier turns the name into an exported Go identifier with the prefix.
+// Parts separated by anything but letters and digits are joined in camel case
+func identifier(prefix, name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, p := range parts {
+		r := []rune(p)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+
+	ret := b.String()
+	if ret == "" || unicode.IsDigit([]rune(ret)[0]) {
+		ret = "_" + ret
+	}
+
+	return ret
+}
+
+// checkDirs scans the Go files for bar names, that are not in the layout.
+// A directory ending with /... is scanned recursively
+func checkDirs(dirs []string, bars []string) ([]string, error) {
+	known := map[string]bool{"super": true}
+	for _, bar := range bars {
+		known[bar] = true
+	}
+
+	problems := []string{}
+	fset := token.NewFileSet()
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		// calls, that can't be resolved, are skipped
+		Error: func(error) {},
+	}
+	checkDir := func(dir string) error {
+		pkgs, err := parseDir(fset, dir)
+		if err != nil {
+			return err
+		}
+		for _, files := range pkgs {
+			info := &types.Info{
+				Uses: make(map[*ast.Ident]types.Object),
+			}
+			conf.Check(dir, fset, files, info)
+			for _, f := range files {
+				ast.Inspect(f, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					idx, ok := barArgs[funcName(info, call.Fun)]
+					if !ok || idx >= len(call.Args) {
+						return true
+					}
+					lit, ok := call.Args[idx].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						return true
+					}
+					name, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						return true
+					}
+					// paths into nested chocolates are checked by their first bar
+					name, _, _ = strings.Cut(name, "/")
+					if !known[name] {
+						problems = append(problems, fmt.Sprintf("%s: unknown bar %q", fset.Position(lit.Pos()), name))
+					}
+					return true
+				})
+			}
+		}
+		return nil
+	}
+
+	for _, dir := range dirs {
+		recursive := strings.HasSuffix(dir, "/...")
+		dir = strings.TrimSuffix(dir, "/...")
+		if dir == "" {
+			dir = "."
+		}
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if path != dir && (!recursive || d.Name() == "vendor" || d.Name() == "testdata" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return checkDir(path)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return problems, nil
+}
+
+// parseDir parses the Go files of the directory grouped by their package
+func parseDir(fset *token.FileSet, dir string) (map[string][]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string][]*ast.File{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		ret[f.Name.Name] = append(ret[f.Name.Name], f)
+	}
+
+	return ret, nil
+}
+
+// funcName returns the name of the called function of chocolate or the
+// method prefixed with the name of its receiver type. Calls of other
+// packages return an empty name
+func funcName(info *types.Info, fun ast.Expr) string {
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	case *ast.IndexExpr:
+		return funcName(info, f.X)
+	case *ast.IndexListExpr:
+		return funcName(info, f.X)
+	default:
+		return ""
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != chocolatePkg {
+		return ""
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return fn.Name()
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	return named.Obj().Name() + "." + fn.Name()
+}
@@ -0,0 +1,197 @@
+package chocolate
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type borderlessSetter interface {
+	setBorderless(bool)
+}
+
+func withoutBorder(s lipgloss.Style) lipgloss.Style {
+	return s.
+		BorderTop(false).
+		BorderRight(false).
+		BorderBottom(false).
+		BorderLeft(false)
+}
+
+const (
+	edgeUp uint8 = 1 << iota
+	edgeDown
+	edgeLeft
+	edgeRight
+)
+
+// collapsedBorder draws the borders of all bars of a layout along
+// their resolved rectangles. Edges of bars next to each other are
+// merged and joined by the junction characters of the border.
+type collapsedBorder struct {
+	border lipgloss.Border
+	style  lipgloss.Style
+}
+
+func (cb *collapsedBorder) glyph(edges uint8) string {
+	b := cb.border
+	n := lipgloss.NormalBorder()
+	pick := func(v, fallback string) string {
+		if v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	switch edges {
+	case edgeLeft | edgeRight, edgeLeft, edgeRight:
+		return pick(b.Top, n.Top)
+	case edgeUp | edgeDown, edgeUp, edgeDown:
+		return pick(b.Left, n.Left)
+	case edgeDown | edgeRight:
+		return pick(b.TopLeft, n.TopLeft)
+	case edgeDown | edgeLeft:
+		return pick(b.TopRight, n.TopRight)
+	case edgeUp | edgeRight:
+		return pick(b.BottomLeft, n.BottomLeft)
+	case edgeUp | edgeLeft:
+		return pick(b.BottomRight, n.BottomRight)
+	case edgeUp | edgeDown | edgeRight:
+		return pick(b.MiddleLeft, n.MiddleLeft)
+	case edgeUp | edgeDown | edgeLeft:
+		return pick(b.MiddleRight, n.MiddleRight)
+	case edgeLeft | edgeRight | edgeDown:
+		return pick(b.MiddleTop, n.MiddleTop)
+	case edgeLeft | edgeRight | edgeUp:
+		return pick(b.MiddleBottom, n.MiddleBottom)
+	case edgeUp | edgeDown | edgeLeft | edgeRight:
+		return pick(b.Middle, n.Middle)
+	}
+
+	return " "
+}
+
+// render draws the borders around the bars into an area of width x height cells.
+// The rectangle of a bar spans from xpos, ypos to xend, yend including the end.
+func (cb *collapsedBorder) render(width, height int, bars []barChild) string {
+	if width < 1 || height < 1 {
+		return ""
+	}
+
+	grid := make([][]uint8, height)
+	for i := range grid {
+		grid[i] = make([]uint8, width)
+	}
+	set := func(x, y int, e uint8) {
+		if x >= 0 && x < width && y >= 0 && y < height {
+			grid[y][x] |= e
+		}
+	}
+
+	for _, b := range bars {
+		if b.isHidden() || b.anyZero() {
+			continue
+		}
+		x0, y0, x1, y1 := b.xpos(), b.ypos(), b.xend(), b.yend()
+		for x := x0; x <= x1; x++ {
+			for _, y := range []int{y0, y1} {
+				if x > x0 {
+					set(x, y, edgeLeft)
+				}
+				if x < x1 {
+					set(x, y, edgeRight)
+				}
+			}
+		}
+		for y := y0; y <= y1; y++ {
+			for _, x := range []int{x0, x1} {
+				if y > y0 {
+					set(x, y, edgeUp)
+				}
+				if y < y1 {
+					set(x, y, edgeDown)
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for y, row := range grid {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		var run strings.Builder
+		spaces := 0
+		flush := func() {
+			if run.Len() > 0 {
+				b.WriteString(cb.style.Render(run.String()))
+				run.Reset()
+			}
+			if spaces > 0 {
+				b.WriteString(strings.Repeat(" ", spaces))
+				spaces = 0
+			}
+		}
+		for _, e := range row {
+			if e == 0 {
+				if run.Len() > 0 {
+					flush()
+				}
+				spaces++
+				continue
+			}
+			if spaces > 0 {
+				flush()
+			}
+			run.WriteString(cb.glyph(e))
+		}
+		flush()
+	}
+
+	return b.String()
+}
+
+// drawLabels draws the titles and footers of the bars into their top
+// and bottom borders. Titles win over the footers of the bars above
+func (cb *collapsedBorder) drawLabels(content string, bars []barChild) string {
+	for _, footer := range []bool{true, false} {
+		for _, b := range bars {
+			l, ok := b.(labeler)
+			if !ok || b.isHidden() || b.anyZero() {
+				continue
+			}
+			title, foot := l.borderLabels()
+			label, row := title, b.ypos()
+			if footer {
+				label, row = foot, b.yend()
+			}
+			if label == nil || label.Text == "" {
+				continue
+			}
+			text, pos, ok := label.place(b.xend() - b.xpos() - 1)
+			if !ok {
+				continue
+			}
+			content = placeOverlay(b.xpos()+1+pos, row, label.textStyle(cb.style).Render(text), content)
+		}
+	}
+
+	return content
+}
+
+func newCollapsedBorder(flavour *chocolateFlavour) *collapsedBorder {
+	ret := &collapsedBorder{
+		border: lipgloss.NormalBorder(),
+		style:  lipgloss.NewStyle(),
+	}
+	if s, ok := flavour.styles[TS_DEFAULT]; ok {
+		if b := s.GetBorderStyle(); b.Top != "" {
+			ret.border = b
+		}
+		ret.style = ret.style.
+			Foreground(s.GetBorderTopForeground()).
+			Background(s.GetBorderTopBackground())
+	}
+
+	return ret
+}
@@ -0,0 +1,46 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCollapsedBorderLabels(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.SetBorderCollapse(true)
+	if err := c.SetBarTitle("Header", TS_DEFAULT, BorderLabel{Text: "Header"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetTitle("body", "text", TS_DEFAULT, BorderLabel{Text: "Body", Align: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetFooter("body", "text", TS_DEFAULT, BorderLabel{Text: "Footer", Align: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+
+	lines := strings.Split(ansi.Strip(c.View()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	header, _ := c.Bar("header")
+	_, y, _, h := header.Rect()
+	tests := []struct {
+		row  int
+		want string
+	}{
+		{0, "┌─ Header ─"},
+		{y + h, "─ Body ─┤"},
+		{9, "─ Footer ─"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(lines[tt.row], tt.want) {
+			t.Errorf("row %d = %q, want it to contain %q", tt.row, lines[tt.row], tt.want)
+		}
+	}
+}
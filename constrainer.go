@@ -1,11 +1,26 @@
 package chocolate
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lithdew/casso"
 )
 
+func (bsc barSizeConstraint) constraint(v casso.Symbol) prioritizedConstraint {
+	strength := bsc.Strength
+	if strength == 0 {
+		strength = REQUIRED
+	}
+
+	return prioritizedConstraint{
+		priority:   casso.Priority(strength),
+		constraint: newLinear(casso.Op(bsc.Relation), bsc.Value, term{v, 1}),
+	}
+}
+
 type chocolateBarConstrainer struct {
 	widthConstraints  []barSizeConstraint
 	heightConstraints []barSizeConstraint
@@ -77,39 +92,107 @@ func newGuideConstrainer() *guideBarConstrainer {
 	}
 }
 
+// hintChecker is implemented by the constrainers, whose size hints
+// can conflict with each other
+type hintChecker interface {
+	hintError() error
+}
+
 type styledBarConstrainer struct {
 	chocolateBarConstrainer
-	style   *lipgloss.Style
-	content *string
+	style      *lipgloss.Style
+	content    *string
+	overflow   OverflowPolicy
+	hinter     SizeHinter
+	borderless bool
+	// hintErr is the conflict of the size hints of the last
+	// sizeConstraints call
+	hintErr error
+}
+
+func (sbc *styledBarConstrainer) hintError() error { return sbc.hintErr }
+
+func (sbc *styledBarConstrainer) setOverflow(v OverflowPolicy) { sbc.overflow = v }
+func (sbc *styledBarConstrainer) setBorderless(v bool)         { sbc.borderless = v }
+
+func (sbc *styledBarConstrainer) withHints(model any) *styledBarConstrainer {
+	if tm, ok := model.(*teaModel); ok {
+		model = tm.Model
+	}
+	if h, ok := model.(SizeHinter); ok {
+		sbc.hinter = h
+	}
+
+	return sbc
 }
 
 func (sbc *styledBarConstrainer) sizeConstraints() (width, height []barSizeConstraint) {
 	wconstant := 1.0
 	hconstant := 1.0
-	if sbc.content != nil {
+	wframe := 0.0
+	hframe := 0.0
+	if sbc.content != nil && sbc.overflow == OVERFLOW_NONE {
 		w, h := lipgloss.Size(*sbc.content)
 		wconstant = float64(w)
 		hconstant = float64(h)
 	}
 	if sbc.style != nil {
-		wconstant += float64(sbc.style.GetHorizontalFrameSize())
-		hconstant += float64(sbc.style.GetVerticalFrameSize())
+		style := *sbc.style
+		if sbc.borderless {
+			style = withoutBorder(style)
+		}
+		wframe = float64(style.GetHorizontalFrameSize())
+		hframe = float64(style.GetVerticalFrameSize())
 	}
 
-	sbc.widthConstraints = []barSizeConstraint{
-		{
-			Relation: GE,
-			Value:    -wconstant,
-		},
+	var wpref, hpref, wmax, hmax int
+	if sbc.hinter != nil {
+		wmin, hmin := sbc.hinter.MinSize()
+		if wmin > 0 {
+			wconstant = float64(wmin)
+		}
+		if hmin > 0 {
+			hconstant = float64(hmin)
+		}
+		wpref, hpref = sbc.hinter.PreferredSize()
+		wmax, hmax = sbc.hinter.MaxSize()
 	}
-	sbc.heightConstraints = []barSizeConstraint{
+
+	var werr, herr error
+	sbc.widthConstraints, werr = hintConstraints("width", wconstant+wframe, wpref, wmax, wframe)
+	sbc.heightConstraints, herr = hintConstraints("height", hconstant+hframe, hpref, hmax, hframe)
+	sbc.hintErr = errors.Join(werr, herr)
+
+	return sbc.chocolateBarConstrainer.sizeConstraints()
+}
+
+// hintConstraints returns the size constraints of the hints. A maximum
+// below the minimum is left out and returned as error
+func hintConstraints(attribute string, minimum float64, preferred, maximum int, frame float64) ([]barSizeConstraint, error) {
+	ret := []barSizeConstraint{
 		{
 			Relation: GE,
-			Value:    -hconstant,
+			Value:    -minimum,
 		},
 	}
+	if preferred > 0 {
+		ret = append(ret, barSizeConstraint{
+			Relation: EQ,
+			Value:    -(float64(preferred) + frame),
+			Strength: STRONG,
+		})
+	}
+	if maximum > 0 && float64(maximum)+frame < minimum {
+		return ret, fmt.Errorf("minimum %s %s is above the maximum %s %d", attribute, formatNumber(minimum-frame), attribute, maximum)
+	}
+	if maximum > 0 {
+		ret = append(ret, barSizeConstraint{
+			Relation: LE,
+			Value:    -(float64(maximum) + frame),
+		})
+	}
 
-	return sbc.chocolateBarConstrainer.sizeConstraints()
+	return ret, nil
 }
 
 func newStyledConstrainer(style *lipgloss.Style, content ...*string) *styledBarConstrainer {
@@ -0,0 +1,76 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+)
+
+// hintedViewer is a viewer with fixed size hints
+type hintedViewer struct {
+	min, max [2]int
+}
+
+func (hv hintedViewer) View() string                       { return "" }
+func (hv hintedViewer) MinSize() (width, height int)       { return hv.min[0], hv.min[1] }
+func (hv hintedViewer) PreferredSize() (width, height int) { return 0, 0 }
+func (hv hintedViewer) MaxSize() (width, height int)       { return hv.max[0], hv.max[1] }
+
+func TestSizeHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewer   hintedViewer
+		conflict string
+		width    int
+	}{
+		{
+			name:   "maximum",
+			viewer: hintedViewer{min: [2]int{5, 1}, max: [2]int{8, 0}},
+			width:  8,
+		},
+		{
+			name:     "minimum above the maximum",
+			viewer:   hintedViewer{min: [2]int{10, 1}, max: [2]int{5, 0}},
+			conflict: "model of bar: minimum width 10 is above the maximum width 5",
+			// the maximum is left out
+			width: 40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChocolate()
+			c.MakeBar("bar", false)
+			if err := c.AddViewBarModel(tt.viewer, "view", "bar", false); err != nil {
+				t.Fatal(err)
+			}
+			c.AddConstraints(NewConstraint().
+				WithTarget("bar").
+				WithTargetAttribute(WIDTH).
+				WithSource("super").
+				WithSourceAttribute(WIDTH).
+				WithStrength(WEAK))
+			c.Resize(40, 10)
+			if c.View() == "" {
+				t.Fatal("layout wasn't rendered")
+			}
+
+			if got := c.bars["bar"].width(); got != tt.width {
+				t.Errorf("bar width = %d, want %d", got, tt.width)
+			}
+			err := c.LayoutError()
+			if tt.conflict == "" {
+				if err != nil {
+					t.Errorf("unexpected layout error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.conflict {
+				t.Errorf("layout error = %v, want %q", err, tt.conflict)
+			}
+			got := strings.Join(explanationsOf(t, c, "bar"), "\n")
+			if !strings.Contains(got, "minimum width 10 is above the maximum width 5") {
+				t.Errorf("explanation doesn't report the conflict:\n%s", got)
+			}
+		})
+	}
+}
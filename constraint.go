@@ -3,6 +3,7 @@ package chocolate
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/lithdew/casso"
@@ -29,6 +30,10 @@ func (ca *ConstraintAttribute) UnmarshalJSON(data []byte) error {
 		*ca = XEND
 	case "YEND":
 		*ca = YEND
+	case "XCENTER":
+		*ca = XCENTER
+	case "YCENTER":
+		*ca = YCENTER
 	default:
 		return fmt.Errorf("unknown attribute '%s'", v)
 	}
@@ -36,6 +41,29 @@ func (ca *ConstraintAttribute) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (ca ConstraintAttribute) String() string {
+	switch ca {
+	case WIDTH:
+		return "width"
+	case HEIGHT:
+		return "height"
+	case XSTART:
+		return "xstart"
+	case YSTART:
+		return "ystart"
+	case XEND:
+		return "xend"
+	case YEND:
+		return "yend"
+	case XCENTER:
+		return "xcenter"
+	case YCENTER:
+		return "ycenter"
+	}
+
+	return fmt.Sprintf("attribute(%d)", ca)
+}
+
 const (
 	WIDTH ConstraintAttribute = iota
 	HEIGHT
@@ -43,6 +71,8 @@ const (
 	YSTART
 	XEND
 	YEND
+	XCENTER
+	YCENTER
 )
 
 type ConstraintRelation uint8
@@ -60,6 +90,8 @@ func (cr *ConstraintRelation) UnmarshalJSON(data []byte) error {
 		*cr = GE
 	case "LE":
 		*cr = LE
+	case "NO_OVERLAP":
+		*cr = NO_OVERLAP
 	default:
 		return fmt.Errorf("unknown relation '%s'", v)
 	}
@@ -67,10 +99,71 @@ func (cr *ConstraintRelation) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (cr ConstraintRelation) String() string {
+	switch cr {
+	case EQ:
+		return "="
+	case GE:
+		return ">="
+	case LE:
+		return "<="
+	case NO_OVERLAP:
+		return "no_overlap"
+	}
+
+	return fmt.Sprintf("relation(%d)", cr)
+}
+
 const (
 	EQ ConstraintRelation = ConstraintRelation(casso.EQ)
 	GE ConstraintRelation = ConstraintRelation(casso.GTE)
 	LE ConstraintRelation = ConstraintRelation(casso.LTE)
+	// NO_OVERLAP places the bars of the constraint next to each other
+	// along the axis without overlapping
+	NO_OVERLAP ConstraintRelation = 16
+)
+
+type LayoutAxis uint8
+
+func (la *LayoutAxis) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch strings.ToUpper(v) {
+	case "", "AUTO":
+		*la = AXIS_AUTO
+	case "HORIZONTAL":
+		*la = AXIS_HORIZONTAL
+	case "VERTICAL":
+		*la = AXIS_VERTICAL
+	default:
+		return fmt.Errorf("unknown axis '%s'", v)
+	}
+
+	return nil
+}
+
+func (la LayoutAxis) String() string {
+	switch la {
+	case AXIS_AUTO:
+		return "auto"
+	case AXIS_HORIZONTAL:
+		return "horizontal"
+	case AXIS_VERTICAL:
+		return "vertical"
+	}
+
+	return fmt.Sprintf("axis(%d)", la)
+}
+
+const (
+	// AXIS_AUTO chooses the axis and order for each pair of bars, that
+	// fits the other constraints best
+	AXIS_AUTO LayoutAxis = iota
+	AXIS_HORIZONTAL
+	AXIS_VERTICAL
 )
 
 type ConstraintStrength float64
@@ -97,6 +190,21 @@ func (cs *ConstraintStrength) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (cs ConstraintStrength) String() string {
+	switch cs {
+	case WEAK:
+		return "weak"
+	case MEDIUM:
+		return "medium"
+	case STRONG:
+		return "strong"
+	case REQUIRED:
+		return "required"
+	}
+
+	return strconv.FormatFloat(float64(cs), 'g', -1, 64)
+}
+
 const (
 	WEAK     ConstraintStrength = ConstraintStrength(casso.Weak)
 	MEDIUM                      = ConstraintStrength(casso.Medium)
@@ -104,23 +212,27 @@ const (
 	REQUIRED                    = ConstraintStrength(casso.Required)
 )
 
-func getAttributeTerms(c ConstraintAttribute, v constraintElement, m float64) []casso.Term {
+func getAttributeTerms(c ConstraintAttribute, v constraintElement, m float64) []term {
 	switch c {
 	case WIDTH:
-		return []casso.Term{v.width.T(m)}
+		return []term{{v.width, m}}
 	case HEIGHT:
-		return []casso.Term{v.height.T(m)}
+		return []term{{v.height, m}}
 	case XSTART:
-		return []casso.Term{v.xpos.T(m)}
+		return []term{{v.xpos, m}}
 	case YSTART:
-		return []casso.Term{v.ypos.T(m)}
+		return []term{{v.ypos, m}}
 	case XEND:
-		return []casso.Term{v.width.T(m), v.xpos.T(m)}
+		return []term{{v.width, m}, {v.xpos, m}}
 	case YEND:
-		return []casso.Term{v.height.T(m), v.ypos.T(m)}
+		return []term{{v.height, m}, {v.ypos, m}}
+	case XCENTER:
+		return []term{{v.width, m / 2}, {v.xpos, m}}
+	case YCENTER:
+		return []term{{v.height, m / 2}, {v.ypos, m}}
 	}
 
-	return []casso.Term{}
+	return []term{}
 }
 
 type Constraint struct {
@@ -132,6 +244,71 @@ type Constraint struct {
 	Constant        float64             `json:"constant"`
 	Multiplier      float64             `json:"multiplier"`
 	Strength        ConstraintStrength  `json:"strength"`
+	Bars            []string            `json:"bars"`
+	Axis            LayoutAxis          `json:"axis"`
+	Var             string              `json:"var"`
+	Inside          string              `json:"inside"`
+}
+
+// String describes the constraint like
+// "menu.width = 0.25 * super.width + 2 (medium)"
+func (c Constraint) String() string {
+	var ret string
+	switch {
+	case c.Relation == NO_OVERLAP:
+		ret = fmt.Sprintf("no_overlap(%s) %s", strings.Join(c.Bars, ", "), c.Axis)
+	case c.Inside != "":
+		ret = fmt.Sprintf("%s inside %s", c.Target, c.Inside)
+	default:
+		rhs := []string{}
+		if c.Source != "" {
+			src := c.Source + "." + c.SourceAttribute.String()
+			if c.Multiplier != 1 {
+				src = formatNumber(c.Multiplier) + " * " + src
+			}
+			rhs = append(rhs, src)
+		}
+		if c.Var != "" {
+			rhs = append(rhs, "$"+c.Var)
+		}
+		if c.Constant != 0 || len(rhs) == 0 {
+			rhs = append(rhs, formatNumber(c.Constant))
+		}
+		ret = fmt.Sprintf("%s.%s %s %s", c.Target, c.TargetAttribute, c.Relation, strings.Join(rhs, " + "))
+	}
+
+	return fmt.Sprintf("%s (%s)", strings.ReplaceAll(ret, "+ -", "- "), c.Strength)
+}
+
+// normalized returns the constraint with the bar names as the layout
+// keys its bars
+func (c Constraint) normalized() Constraint {
+	c.Target = barKey(c.Target)
+	c.Source = barKey(c.Source)
+	c.Inside = barKey(c.Inside)
+	bars := make([]string, len(c.Bars))
+	for i, name := range c.Bars {
+		bars[i] = barKey(name)
+	}
+	c.Bars = bars
+
+	return c
+}
+
+// barNames returns the names of the bars that are constrained
+func (c Constraint) barNames() []string {
+	ret := []string{}
+	if c.Target != "" {
+		ret = append(ret, c.Target)
+	}
+
+	return append(ret, c.Bars...)
+}
+
+// required tells if the constraint is applied as required constraint.
+// Relations between multiple bars are always required
+func (c Constraint) required() bool {
+	return c.Strength >= REQUIRED || c.Relation == NO_OVERLAP || c.Inside != ""
 }
 
 func (c Constraint) WithTarget(v string) Constraint {
@@ -174,6 +351,28 @@ func (c Constraint) WithStrength(v ConstraintStrength) Constraint {
 	return c
 }
 
+func (c Constraint) WithBars(v ...string) Constraint {
+	c.Bars = v
+	return c
+}
+
+func (c Constraint) WithAxis(v LayoutAxis) Constraint {
+	c.Axis = v
+	return c
+}
+
+func (c Constraint) WithInside(v string) Constraint {
+	c.Inside = v
+	return c
+}
+
+// WithVar adds the value of the layout variable to the constant.
+// The value can be changed at runtime with Chocolate.SetVar
+func (c Constraint) WithVar(name string) Constraint {
+	c.Var = name
+	return c
+}
+
 func (c *Constraint) UnmarshalJSON(data []byte) error {
 	c.Source = ""
 	c.Constant = 0
@@ -193,3 +392,22 @@ func NewConstraint() Constraint {
 		Strength:   MEDIUM,
 	}
 }
+
+// NewNoOverlap creates a constraint that guarantees that the bars don't overlap.
+// With AXIS_HORIZONTAL or AXIS_VERTICAL the bars are placed in the given order
+func NewNoOverlap(axis LayoutAxis, bars ...string) Constraint {
+	return NewConstraint().
+		WithRelation(NO_OVERLAP).
+		WithAxis(axis).
+		WithBars(bars...).
+		WithStrength(REQUIRED)
+}
+
+// NewInside creates a constraint that guarantees that the target is
+// placed inside the container
+func NewInside(target string, container string) Constraint {
+	return NewConstraint().
+		WithTarget(target).
+		WithInside(container).
+		WithStrength(REQUIRED)
+}
@@ -0,0 +1,122 @@
+package chocolate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// debugColors are the colours of the outlines by the depth of the bar
+var debugColors = []lipgloss.Color{"9", "10", "11", "12", "13", "14"}
+
+// debugRect is the resolved rectangle of a bar relative to the origin
+// of the chocolate the debug outlines are drawn on
+type debugRect struct {
+	name  string
+	x     int
+	y     int
+	w     int
+	h     int
+	depth int
+}
+
+// SetDebug enables outlining every bar with its name, size and position.
+// Bars of nested chocolates and overlays are outlined as well
+func (c *Chocolate) SetDebug(v bool) { c.debug = v }
+func (c *Chocolate) ToggleDebug()    { c.debug = !c.debug }
+func (c *Chocolate) IsDebug() bool   { return c.debug }
+
+// debugRects collects the rectangles of the visible bars, the nested
+// chocolates and the enabled overlays. The offset is the position of the
+// chocolate and the names are prefixed with the path to the chocolate
+func (c *Chocolate) debugRects(ox, oy, depth int, prefix string) []debugRect {
+	ret := []debugRect{}
+	cx, cy := c.rootModel.contentOffset()
+	ox += cx
+	oy += cy
+
+	for _, name := range c.root.paintOrder() {
+		b, ok := c.bars[name]
+		if !ok || b.isHidden() || b.anyZero() {
+			continue
+		}
+		x := ox + b.xpos() + b.borderInset()
+		y := oy + b.ypos() + b.borderInset()
+		ret = append(ret, debugRect{
+			name:  prefix + name,
+			x:     x,
+			y:     y,
+			w:     b.width() - b.borderInset(),
+			h:     b.height() - b.borderInset(),
+			depth: depth,
+		})
+		if b.current == nil {
+			continue
+		}
+		if nested, ok := b.current.source().(*Chocolate); ok && !nested.root.failed() {
+			bx, by := b.contentOffset()
+			ret = append(ret, nested.debugRects(x+bx, y+by, depth+1, prefix+name+"/")...)
+		}
+	}
+
+	names := make([]string, 0, len(c.overlays))
+	for name, o := range c.overlays {
+		if o.enabled && !o.root.failed() {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.overlays[names[i]].zindex < c.overlays[names[j]].zindex
+	})
+	w, h := c.rootModel.width(), c.rootModel.height()
+	for _, name := range names {
+		o := c.overlays[name]
+		x, y := o.calcPosition(w, h, o.rootModel.width(), o.rootModel.height())
+		x = clamp(x, 0, max(w-o.rootModel.width(), 0))
+		y = clamp(y, 0, max(h-o.rootModel.height(), 0))
+		ret = append(ret, o.debugRects(ox-cx+x, oy-cy+y, depth+1, prefix+name+":")...)
+	}
+
+	return ret
+}
+
+// debugView draws the outlines of the bars on the view
+func (c *Chocolate) debugView(view string) string {
+	for _, r := range c.debugRects(0, 0, 0, "") {
+		view = r.draw(view)
+	}
+
+	return view
+}
+
+// draw outlines the rectangle and writes the name, size and position
+// into the top edge
+func (r debugRect) draw(view string) string {
+	if r.w < 1 || r.h < 1 {
+		return view
+	}
+	style := lipgloss.NewStyle().Foreground(debugColors[r.depth%len(debugColors)])
+	label := fmt.Sprintf("%s %d×%d @%d,%d", r.name, r.w, r.h, r.x, r.y)
+
+	if r.w < 2 || r.h < 2 {
+		return placeOverlay(r.x, r.y, style.Render(truncate.String(label, uint(r.w))), view)
+	}
+
+	inner := r.w - 2
+	label = truncate.String(label, uint(inner))
+	top := "┌" + label + strings.Repeat("─", inner-lipgloss.Width(label)) + "┐"
+	bottom := "└" + strings.Repeat("─", inner) + "┘"
+	side := strings.TrimSuffix(strings.Repeat("│\n", r.h-2), "\n")
+
+	view = placeOverlay(r.x, r.y, style.Render(top), view)
+	view = placeOverlay(r.x, r.y+r.h-1, style.Render(bottom), view)
+	if r.h > 2 {
+		view = placeOverlay(r.x, r.y+1, style.Render(side), view)
+		view = placeOverlay(r.x+r.w-1, r.y+1, style.Render(side), view)
+	}
+
+	return view
+}
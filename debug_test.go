@@ -0,0 +1,27 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDebugOutlinesMixedCaseBars(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+	c.View()
+	c.SetDebug(true)
+
+	rects := c.debugRects(0, 0, 0, "")
+	if len(rects) != 2 {
+		t.Fatalf("got %d outlines, want 2", len(rects))
+	}
+	view := c.View()
+	for _, label := range []string{"header 40×3 @0,0", "body 40×7 @0,3"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("view has no outline labelled %q:\n%s", label, view)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package chocolate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// layoutDocument is the layout file format. For compatibility a plain
+// list of constraints is accepted as well.
+type layoutDocument struct {
+	Constraints []Constraint       `json:"constraints"`
+	Bars        barDocuments       `json:"bars"`
+	Padding     []int              `json:"padding"`
+	Gap         *int               `json:"gap"`
+	Splits      []Split            `json:"splits"`
+	Models      []barModelDocument `json:"models"`
+
+	// declared is set, if the document has an own bars section. Every
+	// bar it uses has to be declared then
+	declared bool
+	// provided are the bars used by the included files and fragments
+	provided []string
+}
+
+// barDocument declares the settings and the models of a bar
+type barDocument struct {
+	Z        *int            `json:"z"`
+	CanHide  *bool           `json:"canhide"`
+	Hidden   *bool           `json:"hidden"`
+	Overflow *OverflowPolicy `json:"overflow"`
+	Selected string          `json:"selected"`
+	Models   []modelDocument `json:"models"`
+}
+
+// modelDocument declares a model of a bar. Without a model spec a static
+// text model is created, otherwise the model is created by the factory
+// registered for the type. Flavoured models use the styles of the flavour,
+// that can be changed by the theme modifiers per style selector
+type modelDocument struct {
+	Name      string                                 `json:"name"`
+	Text      *string                                `json:"text"`
+	Model     *modelSpec                             `json:"model"`
+	Flavoured *bool                                  `json:"flavoured"`
+	Styles    []FlavourStyleSelector                 `json:"styles"`
+	Style     FlavourStyleSelector                   `json:"style"`
+	Overflow  *OverflowPolicy                        `json:"overflow"`
+	Theme     map[FlavourStyleSelector]themeDocument `json:"theme"`
+
+	instance BarModel
+}
+
+// barModelDocument declares a model outside of the bars section
+type barModelDocument struct {
+	Bar string `json:"bar"`
+	modelDocument
+}
+
+func (md modelDocument) flavoured() bool { return md.Flavoured == nil || *md.Flavoured }
+
+// style returns the style of a model, that isn't flavoured. Such models
+// only have the default style, so the theme can't modify others
+func (md modelDocument) style() (lipgloss.Style, error) {
+	ret := lipgloss.NewStyle()
+	for selector, theme := range md.Theme {
+		if FlavourStyleSelector(strings.ToLower(string(selector))) != TS_DEFAULT {
+			return ret, fmt.Errorf("model '%s' isn't flavoured and has no style '%s'", md.Name, selector)
+		}
+		for _, modifier := range theme.modifiers() {
+			ret = modifier(ret)
+		}
+	}
+
+	return ret, nil
+}
+
+// themeDocument declares the theme modifiers of a style
+type themeDocument struct {
+	Border           *themeBorder   `json:"border"`
+	BorderForeground *string        `json:"border_foreground"`
+	BorderBackground *string        `json:"border_background"`
+	Foreground       *string        `json:"foreground"`
+	Background       *string        `json:"background"`
+	Padding          []int          `json:"padding"`
+	Margin           []int          `json:"margin"`
+	Align            *themePosition `json:"align"`
+	VAlign           *themePosition `json:"valign"`
+	Bold             *bool          `json:"bold"`
+	Italic           *bool          `json:"italic"`
+	Faint            *bool          `json:"faint"`
+	Reverse          *bool          `json:"reverse"`
+	Blink            *bool          `json:"blink"`
+}
+
+func (td themeDocument) modifiers() []ThemeStyleModifier {
+	ret := []ThemeStyleModifier{}
+	if td.Border != nil {
+		ret = append(ret, Border(lipgloss.Border(*td.Border)))
+	}
+	if td.BorderForeground != nil {
+		ret = append(ret, BorderForeground(lipgloss.Color(*td.BorderForeground)))
+	}
+	if td.BorderBackground != nil {
+		ret = append(ret, BorderBackground(lipgloss.Color(*td.BorderBackground)))
+	}
+	if td.Foreground != nil {
+		ret = append(ret, Foreground(lipgloss.Color(*td.Foreground)))
+	}
+	if td.Background != nil {
+		ret = append(ret, Background(lipgloss.Color(*td.Background)))
+	}
+	if len(td.Padding) > 0 {
+		ret = append(ret, Padding(td.Padding...))
+	}
+	if len(td.Margin) > 0 {
+		ret = append(ret, Margin(td.Margin...))
+	}
+	if td.Align != nil {
+		ret = append(ret, AlignHorizontal(lipgloss.Position(*td.Align)))
+	}
+	if td.VAlign != nil {
+		ret = append(ret, AlignVertical(lipgloss.Position(*td.VAlign)))
+	}
+	if td.Bold != nil {
+		ret = append(ret, Bold(*td.Bold))
+	}
+	if td.Italic != nil {
+		ret = append(ret, Italic(*td.Italic))
+	}
+	if td.Faint != nil {
+		ret = append(ret, Faint(*td.Faint))
+	}
+	if td.Reverse != nil {
+		ret = append(ret, Reverse(*td.Reverse))
+	}
+	if td.Blink != nil {
+		ret = append(ret, Blink(*td.Blink))
+	}
+
+	return ret
+}
+
+type themeBorder lipgloss.Border
+
+func (tb *themeBorder) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(v) {
+	case "normal":
+		*tb = themeBorder(lipgloss.NormalBorder())
+	case "rounded":
+		*tb = themeBorder(lipgloss.RoundedBorder())
+	case "thick":
+		*tb = themeBorder(lipgloss.ThickBorder())
+	case "double":
+		*tb = themeBorder(lipgloss.DoubleBorder())
+	case "block":
+		*tb = themeBorder(lipgloss.BlockBorder())
+	case "outer_half_block":
+		*tb = themeBorder(lipgloss.OuterHalfBlockBorder())
+	case "inner_half_block":
+		*tb = themeBorder(lipgloss.InnerHalfBlockBorder())
+	case "hidden":
+		*tb = themeBorder(lipgloss.HiddenBorder())
+	case "none":
+		*tb = themeBorder(lipgloss.Border{})
+	default:
+		return fmt.Errorf("unknown border '%s'", v)
+	}
+
+	return nil
+}
+
+type themePosition lipgloss.Position
+
+func (tp *themePosition) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		*tp = themePosition(f)
+		return nil
+	}
+
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(v) {
+	case "left", "top":
+		*tp = themePosition(lipgloss.Left)
+	case "center", "middle":
+		*tp = themePosition(lipgloss.Center)
+	case "right", "bottom":
+		*tp = themePosition(lipgloss.Right)
+	default:
+		return fmt.Errorf("unknown position '%s'", v)
+	}
+
+	return nil
+}
+
+type namedBarDocument struct {
+	name string
+	barDocument
+}
+
+// barDocuments keeps the bars in the order they are declared in the document
+type barDocuments []namedBarDocument
+
+func (bd *barDocuments) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("bars have to be an object")
+	}
+
+	*bd = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		bar := namedBarDocument{
+			name: tok.(string),
+		}
+		if err := dec.Decode(&bar.barDocument); err != nil {
+			return fmt.Errorf("bar '%s': %w", bar.name, err)
+		}
+		*bd = append(*bd, bar)
+	}
+
+	return nil
+}
+
+func parseLayoutDocument(p []byte) (*layoutDocument, error) {
+	ret := &layoutDocument{}
+
+	if bytes.HasPrefix(bytes.TrimSpace(p), []byte("[")) {
+		if err := json.Unmarshal(p, &ret.Constraints); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	if err := json.Unmarshal(p, ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// merge appends the constraints, bars and splits of the other document.
+// Padding and gap are taken from the other document, if set
+func (ld *layoutDocument) merge(other *layoutDocument) {
+	ld.provided = append(ld.provided, other.provided...)
+	ld.Constraints = append(ld.Constraints, other.Constraints...)
+	ld.Bars = append(ld.Bars, other.Bars...)
+	ld.Splits = append(ld.Splits, other.Splits...)
+	ld.Models = append(ld.Models, other.Models...)
+	if len(other.Padding) > 0 {
+		ld.Padding = other.Padding
+	}
+	if other.Gap != nil {
+		ld.Gap = other.Gap
+	}
+}
+
+// prefix adds the prefix to the names of all bars and splits.
+// References to super and to bars outside of the fragment, that start
+// with "/", are kept
+func (ld *layoutDocument) prefix(prefix string) {
+	if prefix == "" {
+		return
+	}
+	ld.renameBars(func(name string) string {
+		if name == "" || strings.ToLower(name) == "super" || strings.HasPrefix(name, "/") {
+			return name
+		}
+		return prefix + name
+	})
+}
+
+// resolveOuter removes the leading "/" of the references to bars
+// outside of the fragments
+func (ld *layoutDocument) resolveOuter() {
+	ld.renameBars(func(name string) string {
+		return strings.TrimPrefix(name, "/")
+	})
+}
+
+// renameBars replaces the names of all bars and splits
+func (ld *layoutDocument) renameBars(rename func(string) string) {
+	for i := range ld.Constraints {
+		c := &ld.Constraints[i]
+		c.Target = rename(c.Target)
+		c.Source = rename(c.Source)
+		c.Inside = rename(c.Inside)
+		for j := range c.Bars {
+			c.Bars[j] = rename(c.Bars[j])
+		}
+	}
+	for i := range ld.Bars {
+		ld.Bars[i].name = rename(ld.Bars[i].name)
+	}
+	for i := range ld.Models {
+		ld.Models[i].Bar = rename(ld.Models[i].Bar)
+	}
+	for i := range ld.Splits {
+		s := &ld.Splits[i]
+		s.Name = rename(s.Name)
+		s.First = rename(s.First)
+		s.Second = rename(s.Second)
+	}
+	for i := range ld.provided {
+		ld.provided[i] = rename(ld.provided[i])
+	}
+}
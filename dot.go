@@ -0,0 +1,142 @@
+package chocolate
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// dotEdge is an edge of the constraint graph
+type dotEdge struct {
+	from  string
+	to    string
+	label string
+	attrs string
+}
+
+// ExportDOT writes the constraint graph of the layout in the Graphviz
+// DOT format. Bars are the nodes and the constraints are edges from the
+// source to the target, labelled with the attributes, the relation, the
+// multiplier, the constant and the strength. Bars without any constraint
+// are drawn dashed
+func (c *Chocolate) ExportDOT(w io.Writer) error {
+	edges := c.root.dotEdges()
+	used := map[string]bool{}
+	for _, e := range edges {
+		used[e.from] = true
+		used[e.to] = true
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph chocolate {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+	fmt.Fprintln(b, "\tnode [shape=box];")
+	fmt.Fprintf(b, "\t%s [shape=doubleoctagon, style=filled, fillcolor=lightgrey];\n", strconv.Quote("super"))
+	for _, name := range c.root.order {
+		attrs := []string{}
+		if child, ok := c.root.children[name]; ok && child.isHidden() {
+			attrs = append(attrs, "fontcolor=grey")
+		}
+		if !used[name] {
+			attrs = append(attrs, "style=dashed", "color=red")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, "\t%s [%s];\n", strconv.Quote(name), strings.Join(attrs, ", "))
+			continue
+		}
+		fmt.Fprintf(b, "\t%s;\n", strconv.Quote(name))
+	}
+	for _, e := range edges {
+		attrs := "label=" + strconv.Quote(e.label)
+		if e.attrs != "" {
+			attrs += ", " + e.attrs
+		}
+		fmt.Fprintf(b, "\t%s -> %s [%s];\n", strconv.Quote(e.from), strconv.Quote(e.to), attrs)
+	}
+	fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
+
+// dotEdges returns the edges of the constraints and splits
+func (c *constraintLayout) dotEdges() []dotEdge {
+	ret := []dotEdge{}
+	for _, con := range c.constraints {
+		con = con.normalized()
+		switch {
+		case con.Relation == NO_OVERLAP:
+			for i := 1; i < len(con.Bars); i++ {
+				ret = append(ret, dotEdge{
+					from:  con.Bars[i-1],
+					to:    con.Bars[i],
+					label: fmt.Sprintf("no_overlap %s", con.Axis),
+					attrs: "style=dashed, arrowhead=none",
+				})
+			}
+		case con.Inside != "":
+			ret = append(ret, dotEdge{
+				from:  con.Inside,
+				to:    con.Target,
+				label: "inside",
+				attrs: "style=dotted",
+			})
+		default:
+			from := con.Source
+			if from == "" {
+				// constraints without source only depend on the constant
+				from = con.Target
+			}
+			ret = append(ret, dotEdge{
+				from:  from,
+				to:    con.Target,
+				label: dotLabel(con),
+				attrs: dotStyle(con.Strength),
+			})
+		}
+	}
+	for _, s := range c.splits {
+		axis := AXIS_HORIZONTAL
+		if s.vertical() {
+			axis = AXIS_VERTICAL
+		}
+		ret = append(ret, dotEdge{
+			from:  s.First,
+			to:    s.Second,
+			label: fmt.Sprintf("split %s\n%s %s", s.Name, axis, formatNumber(s.ratio)),
+			attrs: "style=bold, color=blue",
+		})
+	}
+
+	return ret
+}
+
+// dotLabel describes the constraint like
+// "width = 0.25 * width + 2\n(strong)"
+func dotLabel(con Constraint) string {
+	rhs := []string{}
+	if con.Source != "" {
+		rhs = append(rhs, fmt.Sprintf("%s * %s", formatNumber(con.Multiplier), con.SourceAttribute))
+	}
+	if con.Var != "" {
+		rhs = append(rhs, "$"+con.Var)
+	}
+	rhs = append(rhs, formatNumber(con.Constant))
+
+	return fmt.Sprintf("%s %s %s\n(%s)", con.TargetAttribute, con.Relation, strings.Join(rhs, " + "), con.Strength)
+}
+
+// dotStyle draws stronger constraints with thicker lines
+func dotStyle(strength ConstraintStrength) string {
+	switch {
+	case strength >= REQUIRED:
+		return "penwidth=2"
+	case strength >= STRONG:
+		return "penwidth=1.5"
+	case strength >= MEDIUM:
+		return ""
+	}
+
+	return "style=dashed"
+}
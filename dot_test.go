@@ -0,0 +1,28 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportDOTMixedCaseBars(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := c.ExportDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	dot := b.String()
+	if strings.Contains(dot, "color=red") {
+		t.Errorf("graph has unused bars:\n%s", dot)
+	}
+	if strings.Contains(dot, `"Header"`) || strings.Contains(dot, `"Body"`) {
+		t.Errorf("graph has edges to the raw bar names:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"header" -> "body"`) {
+		t.Errorf("graph has no edge from header to body:\n%s", dot)
+	}
+}
@@ -172,7 +172,10 @@ func main() {
 	// parent to the model, which can be used by setStyle (will be shown later)
 	// If an optional list of FlavourStyleSelector is provided it will just
 	// pass the styles of the flavour to the model, that are specified
-	content := choc.MakeText("content", "mainbar", true, chocolate.TS_FOCUSED)
+	content, err := choc.MakeText("content", "mainbar", true, chocolate.TS_FOCUSED)
+	if err != nil {
+		panic(err)
+	}
 
 	// Set the content for the TextModel
 	content.SetText("This is just a stupid placeholder text")
@@ -191,7 +194,10 @@ func main() {
 	}
 
 	// create a TextModel which is providing the dialog question
-	question := overlay.MakeText("question", "contentbar", true)
+	question, err := overlay.MakeText("question", "contentbar", true)
+	if err != nil {
+		panic(err)
+	}
 	question.SetText("Do you really want to quit?")
 
 	// create the dialog buttons
@@ -0,0 +1,10 @@
+// Code generated by chocolategen from layout.cnf. DO NOT EDIT.
+
+package main
+
+// Bars of layout.cnf
+const (
+	BarMenuheader = "menuheader"
+	BarMenubar    = "menubar"
+	BarContentbar = "contentbar"
+)
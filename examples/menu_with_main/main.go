@@ -157,9 +157,18 @@ func main() {
 	// parent to the model, which can be used by setStyle (will be shown later)
 	// If an optional list of FlavourStyleSelector is provided it will just
 	// pass the styles of the flavour to the model, that are specified
-	f := choc.MakeText("first", "contentbar", true, chocolate.TS_FOCUSED)
-	s := choc.MakeText("second", "contentbar", true, chocolate.TS_FOCUSED)
-	mh := choc.MakeText("menuheader", "menuheader", true)
+	f, err := choc.MakeText("first", "contentbar", true, chocolate.TS_FOCUSED)
+	if err != nil {
+		panic(err)
+	}
+	s, err := choc.MakeText("second", "contentbar", true, chocolate.TS_FOCUSED)
+	if err != nil {
+		panic(err)
+	}
+	mh, err := choc.MakeText("menuheader", "menuheader", true)
+	if err != nil {
+		panic(err)
+	}
 
 	// Set the content for the TextModel
 	f.SetText("First")
@@ -0,0 +1,398 @@
+package chocolate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lithdew/casso"
+)
+
+// ConstraintOrigin tells which part of chocolate added a constraint
+// to the solver
+type ConstraintOrigin uint8
+
+const (
+	// ORIGIN_LAYOUT are the constraints of the layout
+	ORIGIN_LAYOUT ConstraintOrigin = iota
+	// ORIGIN_SIZE are the sizes required by the models of the bars
+	ORIGIN_SIZE
+	// ORIGIN_BOUNDS keep the bars inside the layout
+	ORIGIN_BOUNDS
+	// ORIGIN_SPLIT are the constraints of the splits
+	ORIGIN_SPLIT
+	// ORIGIN_BIAS are injected to share the space between overlapping bars
+	ORIGIN_BIAS
+	// ORIGIN_VAR are the values of the layout variables
+	ORIGIN_VAR
+)
+
+func (co ConstraintOrigin) String() string {
+	switch co {
+	case ORIGIN_LAYOUT:
+		return "layout"
+	case ORIGIN_SIZE:
+		return "size"
+	case ORIGIN_BOUNDS:
+		return "bounds"
+	case ORIGIN_SPLIT:
+		return "split"
+	case ORIGIN_BIAS:
+		return "bias"
+	case ORIGIN_VAR:
+		return "var"
+	}
+
+	return fmt.Sprintf("origin(%d)", co)
+}
+
+// term is a symbol of the solver with its coefficient. Unlike casso.Term
+// it can be read again to explain the solution
+type term struct {
+	sym   casso.Symbol
+	coeff float64
+}
+
+// linearConstraint is the constraint "terms + constant op 0"
+type linearConstraint struct {
+	op       casso.Op
+	constant float64
+	terms    []term
+}
+
+func newLinear(op casso.Op, constant float64, terms ...term) linearConstraint {
+	return linearConstraint{
+		op:       op,
+		constant: constant,
+		terms:    terms,
+	}
+}
+
+// symbolConstraint is the constraint "sym op value"
+func symbolConstraint(sym casso.Symbol, op casso.Op, value float64) linearConstraint {
+	return newLinear(op, -value, term{sym, 1})
+}
+
+func (lc linearConstraint) constraint() casso.Constraint {
+	terms := make([]casso.Term, len(lc.terms))
+	for i, t := range lc.terms {
+		terms[i] = t.sym.T(t.coeff)
+	}
+
+	return casso.NewConstraint(lc.op, lc.constant, terms...)
+}
+
+func (lc linearConstraint) uses(syms ...casso.Symbol) bool {
+	for _, t := range lc.terms {
+		for _, sym := range syms {
+			if t.sym == sym {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// miss returns by how much the solution misses the constraint
+func (lc linearConstraint) miss(solver *casso.Solver) float64 {
+	v := lc.constant
+	for _, t := range lc.terms {
+		v += t.coeff * solver.Val(t.sym)
+	}
+
+	switch lc.op {
+	case casso.GTE:
+		v = max(-v, 0)
+	case casso.LTE:
+		v = max(v, 0)
+	default:
+		v = math.Abs(v)
+	}
+	if v < 1e-6 {
+		return 0
+	}
+
+	return v
+}
+
+// negated returns the same constraint with all signs inverted
+func (lc linearConstraint) negated() linearConstraint {
+	terms := make([]term, len(lc.terms))
+	for i, t := range lc.terms {
+		terms[i] = term{t.sym, -t.coeff}
+	}
+	op := lc.op
+	switch op {
+	case casso.GTE:
+		op = casso.LTE
+	case casso.LTE:
+		op = casso.GTE
+	}
+
+	return newLinear(op, -lc.constant, terms...)
+}
+
+// format writes the constraint as "terms op value" with the names
+// of the symbols. The targets of the layout constraints are stored
+// negated, so the signs are inverted to start with a positive term
+// like the Constraint does
+func (lc linearConstraint) format(names map[casso.Symbol]string) string {
+	if len(lc.terms) > 0 && lc.terms[0].coeff < 0 {
+		lc = lc.negated()
+	}
+
+	var b strings.Builder
+	for i, t := range lc.terms {
+		coeff := t.coeff
+		switch {
+		case i == 0 && coeff < 0:
+			b.WriteString("-")
+			coeff = -coeff
+		case i > 0 && coeff < 0:
+			b.WriteString(" - ")
+			coeff = -coeff
+		case i > 0:
+			b.WriteString(" + ")
+		}
+		if coeff != 1 {
+			b.WriteString(formatNumber(coeff) + " * ")
+		}
+		name, ok := names[t.sym]
+		if !ok {
+			name = "?"
+		}
+		b.WriteString(name)
+	}
+	if len(lc.terms) == 0 {
+		b.WriteString("0")
+	}
+
+	op := "="
+	switch lc.op {
+	case casso.GTE:
+		op = ">="
+	case casso.LTE:
+		op = "<="
+	}
+
+	return fmt.Sprintf("%s %s %s", b.String(), op, formatNumber(0-lc.constant))
+}
+
+// constraintSource is the origin of a constraint added to the solver
+// and the description of what made it
+type constraintSource struct {
+	origin ConstraintOrigin
+	name   string
+}
+
+var (
+	fromLayoutSize = constraintSource{ORIGIN_BOUNDS, "layout size"}
+	fromBounds     = constraintSource{ORIGIN_BOUNDS, "inside layout"}
+)
+
+// appliedConstraint is a constraint that was added to the solver
+type appliedConstraint struct {
+	constraintSource
+	linearConstraint
+	priority casso.Priority
+	rejected bool
+	// variable of a var constraint, whose value may have been
+	// suggested after the constraint was added
+	variable *layoutVar
+	// err is why a layout constraint couldn't be added and bars are
+	// the names it references
+	err  error
+	bars []string
+}
+
+// record keeps the constraint, if it was added to the solver, that
+// is explained
+func (c *constraintLayout) record(solver *casso.Solver, src constraintSource, priority casso.Priority, con linearConstraint, err error) {
+	if solver != c.explained || solver == nil {
+		return
+	}
+	c.applied = append(c.applied, &appliedConstraint{
+		constraintSource: src,
+		linearConstraint: con,
+		priority:         priority,
+		rejected:         err != nil,
+	})
+}
+
+// recordFailed keeps the layout constraint, that couldn't be added to the
+// solver, that is explained, for example because it references unknown bars
+func (c *constraintLayout) recordFailed(solver *casso.Solver, constraint Constraint, err error) {
+	if solver != c.explained || solver == nil {
+		return
+	}
+	con := constraint.normalized()
+	c.applied = append(c.applied, &appliedConstraint{
+		constraintSource: constraintSource{ORIGIN_LAYOUT, constraint.String()},
+		priority:         casso.Priority(constraint.Strength),
+		err:              err,
+		bars:             append(con.barNames(), con.Source, con.Inside),
+	})
+}
+
+// recordError keeps the error of the bars, that was found while adding
+// the constraints to the solver, that is explained
+func (c *constraintLayout) recordError(solver *casso.Solver, src constraintSource, bars []string, err error) {
+	if solver != c.explained || solver == nil {
+		return
+	}
+	c.applied = append(c.applied, &appliedConstraint{
+		constraintSource: src,
+		priority:         casso.Required,
+		err:              err,
+		bars:             bars,
+	})
+}
+
+// Explanation describes a constraint, that affects a bar
+type Explanation struct {
+	Origin ConstraintOrigin
+	// Source is the constraint, split, model or bars that made the constraint
+	Source string
+	// Expression is the constraint as it was added to the solver
+	Expression string
+	Strength   ConstraintStrength
+	Satisfied  bool
+	// Miss is by how much the solution misses the expression
+	Miss float64
+	// Rejected is set for required constraints, that conflict with
+	// other required constraints and couldn't be added
+	Rejected bool
+	// Err is why a layout constraint couldn't be added at all,
+	// for example because one of its bars is unknown
+	Err error
+}
+
+func (e Explanation) String() string {
+	ret := fmt.Sprintf("[%s] %s (%s) from %s", e.Origin, e.Expression, e.Strength, e.Source)
+	switch {
+	case e.Err != nil:
+		ret += fmt.Sprintf(": %v", e.Err)
+	case e.Rejected:
+		ret += ": rejected"
+	case !e.Satisfied:
+		ret += fmt.Sprintf(": violated by %s", formatNumber(e.Miss))
+	}
+
+	return ret
+}
+
+// symbolNames returns the names of the symbols of the bars,
+// the layout and the variables
+func (c *constraintLayout) symbolNames() map[casso.Symbol]string {
+	ret := map[casso.Symbol]string{}
+	add := func(name string, ce constraintElement) {
+		ret[ce.width] = name + ".width"
+		ret[ce.height] = name + ".height"
+		ret[ce.xpos] = name + ".xstart"
+		ret[ce.ypos] = name + ".ystart"
+	}
+	add("super", c.super)
+	for name, child := range c.children {
+		add(name, child.getCelem())
+	}
+	for name, v := range c.vars {
+		ret[v.sym] = "$" + name
+	}
+
+	return ret
+}
+
+// explain returns the constraints of the last resolve, that use
+// the symbols of the bar or reference it and couldn't be added
+func (c *constraintLayout) explain(name string) []Explanation {
+	ret := []Explanation{}
+	child, ok := c.children[name]
+	if c.explained == nil || !ok {
+		return ret
+	}
+	ce := child.getCelem()
+
+	names := c.symbolNames()
+	vars := []casso.Symbol{}
+	for _, v := range c.vars {
+		vars = append(vars, v.sym)
+	}
+	used := []casso.Symbol{}
+	explain := func(a *appliedConstraint) {
+		if a.err != nil {
+			ret = append(ret, Explanation{
+				Origin:     a.origin,
+				Source:     a.name,
+				Expression: a.name,
+				Strength:   ConstraintStrength(a.priority),
+				Rejected:   true,
+				Err:        a.err,
+			})
+			return
+		}
+		con := a.linearConstraint
+		if a.variable != nil {
+			con = symbolConstraint(a.variable.sym, casso.EQ, a.variable.value)
+		}
+		miss := 0.0
+		if !a.rejected {
+			miss = con.miss(c.explained)
+		}
+		ret = append(ret, Explanation{
+			Origin:     a.origin,
+			Source:     a.name,
+			Expression: con.format(names),
+			Strength:   ConstraintStrength(a.priority),
+			Satisfied:  !a.rejected && miss == 0,
+			Miss:       miss,
+			Rejected:   a.rejected,
+		})
+	}
+	for _, a := range c.applied {
+		if a.err != nil {
+			if slices.Contains(a.bars, name) {
+				explain(a)
+			}
+			continue
+		}
+		if a.variable != nil || !a.uses(ce.width, ce.height, ce.xpos, ce.ypos) {
+			continue
+		}
+		explain(a)
+		for _, sym := range vars {
+			if a.uses(sym) && !slices.Contains(used, sym) {
+				used = append(used, sym)
+			}
+		}
+	}
+	// the values of the variables used by the constraints of the bar
+	for _, a := range c.applied {
+		if a.variable != nil && slices.Contains(used, a.variable.sym) {
+			explain(a)
+		}
+	}
+
+	return ret
+}
+
+// Explain returns the constraints of the last resolve, that affect the
+// size and position of the bar. Constraints injected by the bias heuristic
+// and the sizes of the models are included. The layout isn't resolved
+// again, so the result describes the last View
+func (c *Chocolate) Explain(bar string) ([]Explanation, error) {
+	if _, err := c.getBar(bar); err != nil {
+		return nil, err
+	}
+	if c.root.explained == nil {
+		return nil, fmt.Errorf("layout not resolved yet")
+	}
+
+	return c.root.explain(barKey(bar)), nil
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,85 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+)
+
+func explanationsOf(t *testing.T, c *Chocolate, bar string) []string {
+	t.Helper()
+	ex, err := c.Explain(bar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := make([]string, len(ex))
+	for i, e := range ex {
+		ret[i] = e.String()
+	}
+	return ret
+}
+
+func TestExplainMixedCaseBars(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Explain("header"); err == nil {
+		t.Error("Explain before the first resolve didn't fail")
+	}
+	c.Resize(40, 10)
+	c.View()
+
+	got := strings.Join(explanationsOf(t, c, "Header"), "\n")
+	for _, want := range []string{
+		"[layout] header.height = 3 (required)",
+		"[layout] header.width - super.width = 0 (required)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("explanation has no %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestExplainUnknownBars(t *testing.T) {
+	c := NewChocolate()
+	c.AddConstraints(Constraint{
+		Target:          "header",
+		TargetAttribute: HEIGHT,
+		Source:          "nope",
+		SourceAttribute: HEIGHT,
+		Relation:        EQ,
+		Multiplier:      1,
+		Strength:        REQUIRED,
+	})
+	if _, err := c.MakeText("title", "header", true); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+	c.View()
+
+	got := strings.Join(explanationsOf(t, c, "header"), "\n")
+	if !strings.Contains(got, "unknown source: 'nope'") {
+		t.Errorf("explanation doesn't report the unknown source:\n%s", got)
+	}
+}
+
+func TestExplainDoesNotResolve(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(mixedCaseLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+	c.View()
+	before := explanationsOf(t, c, "body")
+
+	// Chocolate.Resize arranges right away, the layout only marks
+	// itself dirty
+	c.root.Resize(40, 20)
+	after := explanationsOf(t, c, "body")
+	if strings.Join(before, "\n") != strings.Join(after, "\n") {
+		t.Errorf("Explain resolved the layout again:\n%s\n---\n%s", strings.Join(before, "\n"), strings.Join(after, "\n"))
+	}
+	if h := c.bars["body"].height(); h != 7 {
+		t.Errorf("Explain changed the bar to the height %d", h)
+	}
+}
@@ -0,0 +1,87 @@
+package chocolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ModelFactory creates a model from the params of a layout file
+type ModelFactory func(params json.RawMessage) (BarModel, error)
+
+var (
+	modelFactoriesMu sync.RWMutex
+	modelFactories   = map[string]ModelFactory{}
+)
+
+// RegisterModelFactory registers the factory for the model type, so layout
+// files can declare models of this type. A factory registered for the same
+// type before is replaced
+func RegisterModelFactory(typeName string, factory ModelFactory) {
+	modelFactoriesMu.Lock()
+	defer modelFactoriesMu.Unlock()
+
+	modelFactories[strings.ToLower(typeName)] = factory
+}
+
+func getModelFactory(typeName string) (ModelFactory, bool) {
+	modelFactoriesMu.RLock()
+	defer modelFactoriesMu.RUnlock()
+
+	f, ok := modelFactories[strings.ToLower(typeName)]
+	return f, ok
+}
+
+// modelSpec selects the factory and its params for a declared model
+type modelSpec struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+}
+
+func (ms *modelSpec) create() (BarModel, error) {
+	factory, ok := getModelFactory(ms.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown model type '%s'", ms.Type)
+	}
+
+	return factory(ms.Params)
+}
+
+// createModels creates the declared models, that don't exist yet. It is
+// done before anything of the document is applied, so a failing factory
+// leaves the layout untouched
+func (c *Chocolate) createModels(doc *layoutDocument) error {
+	create := func(bar string, md *modelDocument) error {
+		if md.Model == nil {
+			return nil
+		}
+		if b, ok := c.bars[barKey(bar)]; ok {
+			if _, ok := b.models[strings.ToLower(md.Name)]; ok {
+				return nil
+			}
+		}
+		model, err := md.Model.create()
+		if err != nil {
+			return fmt.Errorf("bar '%s' model '%s': %w", bar, md.Name, err)
+		}
+		md.instance = model
+
+		return nil
+	}
+
+	for i := range doc.Bars {
+		for j := range doc.Bars[i].Models {
+			if err := create(doc.Bars[i].name, &doc.Bars[i].Models[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range doc.Models {
+		if err := create(doc.Models[i].Bar, &doc.Models[i].modelDocument); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
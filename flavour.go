@@ -114,6 +114,12 @@ func Blink(v bool) ThemeStyleModifier {
 	}
 }
 
+func Bold(v bool) ThemeStyleModifier {
+	return func(s lipgloss.Style) lipgloss.Style {
+		return s.Bold(v)
+	}
+}
+
 func Border(b lipgloss.Border, sides ...bool) ThemeStyleModifier {
 	return func(s lipgloss.Style) lipgloss.Style {
 		return s.Border(b, sides...)
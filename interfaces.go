@@ -7,6 +7,12 @@ import (
 type barSizeConstraint struct {
 	Relation ConstraintRelation
 	Value    float64
+	Strength ConstraintStrength
+}
+
+type prioritizedConstraint struct {
+	priority   casso.Priority
+	constraint linearConstraint
 }
 
 type barConstrainer interface {
@@ -29,6 +35,17 @@ type BarModel interface {
 	Resize(width, height int)
 }
 
+// SizeHinter can be implemented by a BarViewer or BarModel to tell chocolate
+// about the size it needs. The sizes are meant for the content without the
+// frame of the style. Values below 1 are ignored.
+// The minimum and maximum are required by the layout, the preferred size
+// is applied with a strong strength.
+type SizeHinter interface {
+	MinSize() (width, height int)
+	PreferredSize() (width, height int)
+	MaxSize() (width, height int)
+}
+
 type barContainer interface {
 	setDirty()
 }
@@ -36,7 +53,7 @@ type barContainer interface {
 type barChild interface {
 	barConstrainer
 	BarModel
-	getInitConstraints() []casso.Constraint
+	getInitConstraints() []prioritizedConstraint
 	getCelem() constraintElement
 	update(*casso.Solver)
 	width() int
@@ -47,4 +64,14 @@ type barChild interface {
 	yend() int
 	anyZero() bool
 	setParent(parent barContainer)
+	canHide() bool
+	isHidden() bool
+	isAutoHidden() bool
+	autoHide() bool
+	autoUnhide()
+	hidePriority() int
+	minSize() (width, height int)
+	zindex() int
+	setBorderInset(int)
+	borderInset() int
 }
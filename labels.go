@@ -0,0 +1,176 @@
+package chocolate
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// BorderLabel is a text drawn into the top (title) or bottom (footer)
+// border of a model. If Style is set, the text is rendered with the style
+// of the flavour, otherwise the colors of the border are used
+type BorderLabel struct {
+	Text  string
+	Align lipgloss.Position
+	Style FlavourStyleSelector
+}
+
+type borderLabel struct {
+	BorderLabel
+	style *lipgloss.Style
+}
+
+type borderLabels struct {
+	title  *borderLabel
+	footer *borderLabel
+}
+
+type styledRenderer interface {
+	getStyle() lipgloss.Style
+}
+
+// labeler returns the title and footer, that are currently shown
+type labeler interface {
+	borderLabels() (title *borderLabel, footer *borderLabel)
+}
+
+func newBorderLabel(label BorderLabel, flavour *chocolateFlavour) *borderLabel {
+	ret := &borderLabel{
+		BorderLabel: label,
+	}
+	if label.Style != "" && flavour != nil {
+		ret.style = flavour.styles[FlavourStyleSelector(strings.ToLower(string(label.Style)))]
+	}
+
+	return ret
+}
+
+// setLabel stores the title or footer for the style selector
+func setLabel(labels map[FlavourStyleSelector]*borderLabels, style FlavourStyleSelector, label *borderLabel, footer bool) map[FlavourStyleSelector]*borderLabels {
+	if labels == nil {
+		labels = make(map[FlavourStyleSelector]*borderLabels)
+	}
+	s := FlavourStyleSelector(strings.ToLower(string(style)))
+	if labels[s] == nil {
+		labels[s] = &borderLabels{}
+	}
+	if footer {
+		labels[s].footer = label
+	} else {
+		labels[s].title = label
+	}
+
+	return labels
+}
+
+// getLabels returns the labels for the style selector falling back
+// to the labels of the default style
+func getLabels(labels map[FlavourStyleSelector]*borderLabels, style FlavourStyleSelector) (title *borderLabel, footer *borderLabel) {
+	for _, s := range []FlavourStyleSelector{style, TS_DEFAULT} {
+		if l, ok := labels[s]; ok {
+			if title == nil {
+				title = l.title
+			}
+			if footer == nil {
+				footer = l.footer
+			}
+		}
+	}
+
+	return title, footer
+}
+
+// drawLabel draws the label into the top or bottom border of the
+// rendered content. Content without the border is returned untouched
+func drawLabel(content string, style lipgloss.Style, label *borderLabel, footer bool) string {
+	if label == nil || label.Text == "" {
+		return content
+	}
+	if (!footer && style.GetBorderTopSize() < 1) || (footer && style.GetBorderBottomSize() < 1) {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	row := style.GetMarginTop()
+	if footer {
+		row = len(lines) - 1 - style.GetMarginBottom()
+	}
+	if row < 0 || row >= len(lines) {
+		return content
+	}
+
+	line := lines[row]
+	width := lipgloss.Width(line)
+	ml := style.GetMarginLeft()
+	mr := style.GetMarginRight()
+	border := style.GetBorderStyle()
+
+	left, right, edge := border.TopLeft, border.TopRight, border.Top
+	edgeStyle := lipgloss.NewStyle().
+		Foreground(style.GetBorderTopForeground()).
+		Background(style.GetBorderTopBackground())
+	if footer {
+		left, right, edge = border.BottomLeft, border.BottomRight, border.Bottom
+		edgeStyle = lipgloss.NewStyle().
+			Foreground(style.GetBorderBottomForeground()).
+			Background(style.GetBorderBottomBackground())
+	}
+	if style.GetBorderLeftSize() < 1 {
+		left = ""
+	}
+	if style.GetBorderRightSize() < 1 {
+		right = ""
+	}
+
+	inner := width - ml - mr - lipgloss.Width(left) - lipgloss.Width(right)
+	if edge == "" {
+		return content
+	}
+	text, pos, ok := label.place(inner)
+	if !ok {
+		return content
+	}
+	tw := lipgloss.Width(text)
+
+	var b strings.Builder
+	b.WriteString(truncate.String(line, uint(ml)))
+	b.WriteString(edgeStyle.Render(left + strings.Repeat(edge, pos)))
+	b.WriteString(label.textStyle(edgeStyle).Render(text))
+	b.WriteString(edgeStyle.Render(strings.Repeat(edge, inner-pos-tw) + right))
+	if mr > 0 {
+		b.WriteString(cutLeft(line, width-mr))
+	}
+	lines[row] = b.String()
+
+	return strings.Join(lines, "\n")
+}
+
+// place returns the text of the label fitted into a border of inner
+// cells between the corners and its position in the border
+func (bl *borderLabel) place(inner int) (text string, pos int, ok bool) {
+	if inner < 3 {
+		return "", 0, false
+	}
+	text = " " + bl.Text + " "
+	if lipgloss.Width(text) > inner-2 {
+		text = truncate.StringWithTail(text, uint(inner-2), ellipsis)
+	}
+	tw := lipgloss.Width(text)
+	pos = clamp(int(float64(inner-tw)*float64(bl.Align)), 1, inner-tw-1)
+
+	return text, pos, true
+}
+
+// textStyle returns the style of the text of the label. Labels
+// without an own style use the style of the border
+func (bl *borderLabel) textStyle(edgeStyle lipgloss.Style) lipgloss.Style {
+	if bl.style == nil {
+		return edgeStyle
+	}
+	return lipgloss.NewStyle().
+		Foreground(bl.style.GetForeground()).
+		Background(bl.style.GetBackground()).
+		Bold(bl.style.GetBold()).
+		Italic(bl.style.GetItalic())
+}
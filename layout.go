@@ -1,8 +1,9 @@
 package chocolate
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"sort"
 	"strings"
@@ -21,14 +22,42 @@ type constraintElement struct {
 type constraintLayout struct {
 	width       int
 	height      int
+	super       constraintElement
 	children    map[string]barChild
+	order       []string
 	constraints []Constraint
+	splits      []*layoutSplit
+	// separation of the pairs of bars of the no_overlap
+	// constraints with AXIS_AUTO and what it was chosen for.
+	// It is reset, when the constraints or the bars change
+	apart       map[barPair]apartChoice
+	apartFor    apartKey
+	vars        map[string]*layoutVar
+	varsChanged bool
+	// solver of the last resolve, if it can be used to apply
+	// changed variables
+	solver *casso.Solver
+	// solver of the last resolve and the constraints added to it
+	explained   *casso.Solver
+	applied     []*appliedConstraint
+	padding     [4]int
+	gap         int
+	collapsed   *collapsedBorder
 	failsMax    int
 	dirty       bool
+	unsatisfied bool
+	err         error
+	conflict    error
+
+	// automatically hidden bars of the last report and the reports,
+	// that weren't returned by Chocolate.Update yet
+	reported   []string
+	autoHides  []AutoHideMsg
+	onAutoHide func(AutoHideMsg)
 }
 
 func (c *constraintLayout) addBar(n string, v barChild) bool {
-	name := strings.ToLower(n)
+	name := barKey(n)
 	if name == "super" {
 		return false
 	}
@@ -36,105 +65,353 @@ func (c *constraintLayout) addBar(n string, v barChild) bool {
 	if c.children == nil {
 		c.children = make(map[string]barChild)
 	}
+	if _, ok := c.children[name]; !ok {
+		c.order = append(c.order, name)
+	}
 	v.setParent(c)
+	v.setBorderInset(c.borderInset())
 	c.children[name] = v
 
+	c.apart = nil
 	c.dirty = true
 	return true
 }
 
+func (c *constraintLayout) removeBar(n string) {
+	name := barKey(n)
+	if _, ok := c.children[name]; !ok {
+		return
+	}
+	delete(c.children, name)
+	c.order = slices.DeleteFunc(c.order, func(v string) bool { return v == name })
+	c.apart = nil
+	c.dirty = true
+}
+
 func (c *constraintLayout) Resize(width, height int) {
-	// s := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
 	if c.width != width || c.height != height {
 		c.setDirty()
-		// c.width = width - s.GetHorizontalFrameSize()
-		// c.height = height - s.GetVerticalFrameSize()
 	}
 	c.width = width
 	c.height = height
 }
 
-func (c *constraintLayout) View() string {
-	// s := lipgloss.NewStyle().Border(lipgloss.NormalBorder())
+// setPadding sets the insets of the layout in the same order
+// as lipgloss does for paddings and margins
+func (c *constraintLayout) setPadding(i ...int) {
+	top, right, bottom, left, ok := whichSides(i...)
+	if !ok {
+		return
+	}
+	c.padding = [4]int{top, right, bottom, left}
+	c.setDirty()
+}
+
+// setCollapsed enables the collapsed borders drawn by the layout
+// or disables them with nil
+func (c *constraintLayout) setCollapsed(v *collapsedBorder) {
+	c.collapsed = v
+	for _, child := range c.children {
+		child.setBorderInset(c.borderInset())
+	}
+	c.setDirty()
+}
+
+func (c *constraintLayout) borderInset() int {
+	if c.collapsed != nil {
+		return 1
+	}
+	return 0
+}
+
+func (c *constraintLayout) setGap(v int) {
+	c.gap = v
+	c.apart = nil
+	c.setDirty()
+}
+
+// innerSize returns the size of the layout without the padding
+func (c *constraintLayout) innerSize() (int, int) { return c.innerWidth(), c.innerHeight() }
+func (c *constraintLayout) innerWidth() int {
+	return max(c.width-c.padding[1]-c.padding[3]-c.borderInset(), 0)
+}
+
+func (c *constraintLayout) innerHeight() int {
+	return max(c.height-c.padding[0]-c.padding[2]-c.borderInset(), 0)
+}
 
-	bars, err := c.resolve(0)
+func (c *constraintLayout) View() string {
+	bars, err := c.arrange()
+	c.conflict = nil
+	if err == nil && c.unsatisfied {
+		// conflicting required constraints only prevent the rendering,
+		// if the layout doesn't fit, otherwise the bars are shown as
+		// good as the solver could place them
+		c.conflict = c.rejected()
+		if c.exceedsSize() {
+			err = c.conflict
+		}
+	}
+	if c.conflict == nil {
+		c.conflict = c.hintConflicts()
+	}
+	c.err = err
 	if err != nil {
 		return ""
 	}
 
-	// ret := s.Width(c.width).
-	// 	Height(c.height).
-	// 	BorderBottom(false).
-	// 	BorderTop(false).
-	// 	BorderLeft(false).
-	// 	BorderRight(false).
-	// 	Render("")
-
 	ret := lipgloss.Place(c.width, c.height, 0, 0, "")
+	order := c.paintOrder()
+	if c.collapsed != nil {
+		children := make([]barChild, 0, len(order))
+		for _, name := range order {
+			children = append(children, bars[name])
+		}
+		ret = c.collapsed.render(c.width, c.height, children)
+		ret = c.collapsed.drawLabels(ret, children)
+	}
 
-	for _, b := range bars {
-		xPos := b.xpos()
-		yPos := b.ypos()
+	for _, name := range order {
+		b := bars[name]
+		xPos := b.xpos() + b.borderInset()
+		yPos := b.ypos() + b.borderInset()
 		ret = placeOverlay(xPos, yPos, b.View(), ret)
 		if c.dirty {
 			return c.View()
 		}
 	}
 
-	// ret = s.Width(c.width).
-	// 	Height(c.height).
-	// 	Render(ret)
 	return ret
 }
 
-func (c *constraintLayout) setDirty() { c.dirty = true }
+// paintOrder returns the names of the bars in the order they have to
+// be painted. Bars are sorted by their z index and bars with the same
+// index are kept in the order they were declared
+func (c *constraintLayout) paintOrder() []string {
+	ret := slices.Clone(c.order)
+	slices.SortStableFunc(ret, func(a, b string) int {
+		return c.children[a].zindex() - c.children[b].zindex()
+	})
+
+	return ret
+}
+
+// barAt returns the name of the topmost shown bar at the position
+func (c *constraintLayout) barAt(x, y int) (string, bool) {
+	order := c.paintOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		b := c.children[order[i]]
+		if b.isHidden() || b.anyZero() {
+			continue
+		}
+		if x >= b.xpos() && x < b.xend() && y >= b.ypos() && y < b.yend() {
+			return order[i], true
+		}
+	}
+
+	return "", false
+}
+
+func (c *constraintLayout) setDirty()    { c.dirty = true }
+func (c *constraintLayout) failed() bool { return c.err != nil }
+
+// layoutError returns why the layout couldn't be rendered or the
+// conflict of the required constraints or size hints of the rendered layout
+func (c *constraintLayout) layoutError() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.conflict
+}
+
+// hintConflicts returns the conflicting size hints of the models,
+// that were found by the last resolve
+func (c *constraintLayout) hintConflicts() error {
+	errs := []error{}
+	for _, a := range c.applied {
+		if a.origin == ORIGIN_SIZE && a.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.name, a.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// rejected returns the error describing the required constraints,
+// that were rejected by the solver of the last resolve
+func (c *constraintLayout) rejected() error {
+	names := []string{}
+	for _, a := range c.applied {
+		if a.rejected && !slices.Contains(names, a.name) {
+			names = append(names, a.name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unsatisfiable")
+	}
+
+	return fmt.Errorf("unsatisfiable required constraints: %s", strings.Join(names, "; "))
+}
 
 func (c *constraintLayout) addConstraints(constraints ...Constraint) {
 	c.constraints = append(c.constraints, constraints...)
+	c.declareVars(constraints)
+	c.apart = nil
 	c.dirty = true
 }
 
-func (c *constraintLayout) resolve(f int) (map[string]barChild, error) {
+// arrange resolves the layout and hides bars that can be hidden, ordered by
+// their priority, as long as the layout doesn't fit. Bars that were hidden
+// this way are shown again, when the space is available again.
+func (c *constraintLayout) arrange() (map[string]barChild, error) {
+	if c.varsChanged && !c.dirty {
+		c.varsChanged = false
+		if !c.suggestVars() {
+			c.dirty = true
+		}
+	}
+	c.varsChanged = false
 	if !c.dirty {
 		return c.children, nil
 	}
 
-	if f > c.failsMax {
-		return nil, fmt.Errorf("unresolvable")
+	for _, name := range c.autoHidden() {
+		c.children[name].autoUnhide()
 	}
-	solver := casso.NewSolver()
 
-	for _, child := range c.children {
-		for _, con := range child.getInitConstraints() {
-			solver.AddConstraintWithPriority(casso.Required, con)
+	var bars map[string]barChild
+	var err error
+	for {
+		bars, err = c.resolve(0)
+		if err == nil && !c.tooSmall() && !(c.unsatisfied && c.exceedsSize()) {
+			break
+		}
+		name, ok := c.nextAutoHide()
+		if !ok {
+			break
 		}
-		ce := child.getCelem()
-		solver.AddConstraintWithPriority(casso.Required, casso.NewConstraint(casso.GTE, 0, ce.xpos.T(1)))
-		solver.AddConstraintWithPriority(casso.Required, casso.NewConstraint(casso.GTE, 0, ce.ypos.T(1)))
-		solver.AddConstraintWithPriority(casso.Required, casso.NewConstraint(casso.LTE, -float64(c.width), ce.xpos.T(1), ce.width.T(1)))
-		solver.AddConstraintWithPriority(casso.Required, casso.NewConstraint(casso.LTE, -float64(c.height), ce.ypos.T(1), ce.height.T(1)))
+		c.children[name].autoHide()
+		c.dirty = true
+	}
 
-		// if child.CWidth > 0 {
-		// 	solver.AddConstraintWithPriority(casso.Medium, casso.NewConstraint(casso.EQ, -float64(child.CWidth), elements[k].width.T(1)))
-		// }
-		// if child.Height > 0 {
-		// 	solver.AddConstraintWithPriority(casso.Required, casso.NewConstraint(casso.GTE, -float64(child.Height), elements[k].height.T(1)))
-		// }
+	c.reportAutoHidden()
+
+	return bars, err
+}
+
+// reportAutoHidden queues a message, if the automatically hidden bars
+// differ from the ones of the last report
+func (c *constraintLayout) reportAutoHidden() {
+	after := c.autoHidden()
+	if slices.Equal(c.reported, after) {
+		return
 	}
 
-	for _, constraint := range c.constraints {
-		if err := c.parseConstraint(solver, constraint); err != nil {
-			// fmt.Printf("FUFUF: %v\n", constraint)
-			// TODO: error handling
+	msg := AutoHideMsg{
+		Hidden:   []string{},
+		Unhidden: []string{},
+	}
+	for _, name := range after {
+		if !slices.Contains(c.reported, name) {
+			msg.Hidden = append(msg.Hidden, name)
+		}
+	}
+	for _, name := range c.reported {
+		if !slices.Contains(after, name) {
+			msg.Unhidden = append(msg.Unhidden, name)
+		}
+	}
+	c.reported = after
+	c.autoHides = append(c.autoHides, msg)
+	if c.onAutoHide != nil {
+		c.onAutoHide(msg)
+	}
+}
+
+func (c *constraintLayout) autoHidden() []string {
+	ret := []string{}
+	for name, child := range c.children {
+		if child.isAutoHidden() {
+			ret = append(ret, name)
+		}
+	}
+	slices.Sort(ret)
+
+	return ret
+}
+
+func (c *constraintLayout) tooSmall() bool {
+	for _, child := range c.children {
+		if child.isHidden() {
+			continue
+		}
+		w, h := child.minSize()
+		if child.width() < w || child.height() < h {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *constraintLayout) nextAutoHide() (string, bool) {
+	ret := ""
+	for name, child := range c.children {
+		if !child.canHide() || child.isHidden() {
+			continue
+		}
+		if ret == "" {
+			ret = name
 			continue
 		}
+		cur := c.children[ret]
+		if child.hidePriority() < cur.hidePriority() ||
+			(child.hidePriority() == cur.hidePriority() && name < ret) {
+			ret = name
+		}
+	}
+
+	return ret, ret != ""
+}
+
+func (c *constraintLayout) add(solver *casso.Solver, src constraintSource, priority casso.Priority, con linearConstraint) error {
+	_, err := solver.AddConstraintWithPriority(priority, con.constraint())
+	if err != nil && priority >= casso.Required {
+		c.unsatisfied = true
+	}
+	c.record(solver, src, priority, con, err)
+
+	return err
+}
+
+func (c *constraintLayout) resolve(f int) (map[string]barChild, error) {
+	if !c.dirty {
+		return c.children, nil
 	}
 
+	if f > c.failsMax {
+		return nil, fmt.Errorf("unresolvable")
+	}
+	solver := casso.NewSolver()
+	c.solver = nil
+	c.explained = solver
+	c.applied = nil
+	c.unsatisfied = false
+
+	if f == 0 {
+		c.chooseApart()
+	}
+	c.addLayoutSize(solver)
+	c.applyConstraints(solver, false)
+	c.addVars(solver, true)
+
+	biased := false
 	for f <= c.failsMax {
 		for _, v := range c.children {
 			v.update(solver)
 		}
 		if ok := c.bias(solver); ok {
+			biased = true
 			f++
 			continue
 		} else {
@@ -150,10 +427,94 @@ func (c *constraintLayout) resolve(f int) (map[string]barChild, error) {
 		}
 	}
 
+	if !biased {
+		c.solver = solver
+	}
 	c.dirty = false
 	return c.children, nil
 }
 
+// addLayoutSize fixes the layout to its current size
+func (c *constraintLayout) addLayoutSize(solver *casso.Solver) {
+	width, height := c.innerSize()
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.xpos, casso.EQ, float64(c.padding[3])))
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.ypos, casso.EQ, float64(c.padding[0])))
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.width, casso.EQ, float64(width)))
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.height, casso.EQ, float64(height)))
+}
+
+// applyConstraints adds the constraints of the children and the layout to the solver.
+// If minimal is set, all non required constraints are left out and the
+// minimum sizes of the shown bars are added.
+func (c *constraintLayout) applyConstraints(solver *casso.Solver, minimal bool) {
+	for name, child := range c.children {
+		src := constraintSource{ORIGIN_SIZE, "model of " + name}
+		for _, con := range child.getInitConstraints() {
+			if minimal && con.priority < casso.Required {
+				continue
+			}
+			c.add(solver, src, con.priority, con.constraint)
+		}
+		if hc, ok := child.(hintChecker); ok && !child.isHidden() {
+			if err := hc.hintError(); err != nil {
+				c.recordError(solver, src, []string{name}, err)
+			}
+		}
+		if w, h := child.minSize(); minimal && !child.isHidden() {
+			ce := child.getCelem()
+			src := constraintSource{ORIGIN_SIZE, "minimum size of " + name}
+			c.add(solver, src, casso.Required, symbolConstraint(ce.width, casso.GTE, float64(w)))
+			c.add(solver, src, casso.Required, symbolConstraint(ce.height, casso.GTE, float64(h)))
+		}
+		c.addBounds(solver, child.getCelem())
+	}
+
+	for _, constraint := range c.constraints {
+		if minimal && !constraint.required() {
+			continue
+		}
+		if err := c.parseConstraint(solver, constraint); err != nil {
+			c.recordFailed(solver, constraint, err)
+		}
+	}
+
+	for _, split := range c.splits {
+		c.parseSplit(solver, split, minimal)
+	}
+}
+
+func (c *constraintLayout) addBounds(solver *casso.Solver, ce constraintElement) {
+	c.add(solver, fromBounds, casso.Required, newLinear(casso.GTE, 0, term{ce.xpos, 1}, term{c.super.xpos, -1}))
+	c.add(solver, fromBounds, casso.Required, newLinear(casso.GTE, 0, term{ce.ypos, 1}, term{c.super.ypos, -1}))
+	c.add(solver, fromBounds, casso.Required, newLinear(casso.LTE, 0, term{ce.xpos, 1}, term{ce.width, 1}, term{c.super.xpos, -1}, term{c.super.width, -1}))
+	c.add(solver, fromBounds, casso.Required, newLinear(casso.LTE, 0, term{ce.ypos, 1}, term{ce.height, 1}, term{c.super.ypos, -1}, term{c.super.height, -1}))
+}
+
+// minSize calculates the minimum size of the layout that is needed to
+// fulfill all required constraints of the currently shown bars
+func (c *constraintLayout) minSize() (int, int) {
+	unsatisfied := c.unsatisfied
+	defer func() { c.unsatisfied = unsatisfied }()
+
+	solver := casso.NewSolver()
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.xpos, casso.EQ, float64(c.padding[3])))
+	c.add(solver, fromLayoutSize, casso.Required, symbolConstraint(c.super.ypos, casso.EQ, float64(c.padding[0])))
+	c.applyConstraints(solver, true)
+	c.addVars(solver, false)
+	c.add(solver, fromLayoutSize, casso.Strong, symbolConstraint(c.super.width, casso.EQ, 0))
+	c.add(solver, fromLayoutSize, casso.Strong, symbolConstraint(c.super.height, casso.EQ, 0))
+
+	return int(math.Ceil(solver.Val(c.super.width))) + c.padding[1] + c.padding[3] + c.borderInset(),
+		int(math.Ceil(solver.Val(c.super.height))) + c.padding[0] + c.padding[2] + c.borderInset()
+}
+
+// exceedsSize tells if the required constraints of the shown bars need
+// more space than the layout has
+func (c *constraintLayout) exceedsSize() bool {
+	w, h := c.minSize()
+	return c.width < w || c.height < h
+}
+
 func (c *constraintLayout) bias(solver *casso.Solver) bool {
 	// return false
 	for k, child := range c.children {
@@ -176,6 +537,9 @@ func (c *constraintLayout) bias(solver *casso.Solver) bool {
 				xbias := 0.0
 				ybias := 0.0
 				v := c.children[b]
+				if c.isInside(b, kk) || c.isInside(kk, b) {
+					continue
+				}
 
 				if v.xpos() >= vv.xpos() && v.xpos() < vv.xend() {
 					xbias = float64(vv.xend()) - float64(v.xpos())
@@ -212,14 +576,15 @@ func (c *constraintLayout) bias(solver *casso.Solver) bool {
 				total += float64(c.children[id].width())
 				// terms = append(terms, elements[id].height.T(-1))
 			}
-			if total > float64(c.width) {
-				total = float64(c.width)
+			if limit := float64(c.innerWidth() - c.gap*(l-1)); total > limit {
+				total = limit
 			}
-			solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, total, c.children[xbiases[0]].getCelem().width.T(-float64(l))))
+			src := constraintSource{ORIGIN_BIAS, "overlap of " + strings.Join(xbiases, ", ")}
+			c.add(solver, src, casso.Strong, newLinear(casso.EQ, total, term{c.children[xbiases[0]].getCelem().width, -float64(l)}))
 			for i := 1; i < l; i++ {
 				// idp := xbiases[i-1]
 				id := xbiases[i]
-				solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, total, c.children[id].getCelem().width.T(-float64(l))))
+				c.add(solver, src, casso.Strong, newLinear(casso.EQ, total, term{c.children[id].getCelem().width, -float64(l)}))
 				// solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, 0, elements[idp].width.T(-1), elements[id].width.T(1)))
 				// solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, 0, elements[idp].ypos.T(1), elements[idp].height.T(1), elements[id].ypos.T(-1)))
 			}
@@ -235,14 +600,15 @@ func (c *constraintLayout) bias(solver *casso.Solver) bool {
 				total += float64(c.children[id].height())
 				// terms = append(terms, elements[id].height.T(-1))
 			}
-			if total > float64(c.height) {
-				total = float64(c.height)
+			if limit := float64(c.innerHeight() - c.gap*(l-1)); total > limit {
+				total = limit
 			}
-			solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, total, c.children[ybiases[0]].getCelem().height.T(-float64(l))))
+			src := constraintSource{ORIGIN_BIAS, "overlap of " + strings.Join(ybiases, ", ")}
+			c.add(solver, src, casso.Strong, newLinear(casso.EQ, total, term{c.children[ybiases[0]].getCelem().height, -float64(l)}))
 			for i := 1; i < l; i++ {
 				// idp := ybiases[i-1]
 				id := ybiases[i]
-				solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, total, c.children[id].getCelem().height.T(-float64(l))))
+				c.add(solver, src, casso.Strong, newLinear(casso.EQ, total, term{c.children[id].getCelem().height, -float64(l)}))
 				// solver.AddConstraintWithPriority(casso.Strong, casso.NewConstraint(casso.EQ, 0, elements[idp].height.T(-1), elements[id].height.T(1)))
 				// solver.AddConstraint(casso.NewConstraint(casso.EQ, 0, elements[idp].ypos.T(1), elements[idp].height.T(1), elements[id].ypos.T(-1)))
 			}
@@ -256,6 +622,14 @@ func (c *constraintLayout) bias(solver *casso.Solver) bool {
 }
 
 func (c *constraintLayout) parseConstraint(solver *casso.Solver, constraint Constraint) error {
+	constraint = constraint.normalized()
+	if constraint.Relation == NO_OVERLAP {
+		return c.parseNoOverlap(solver, constraint)
+	}
+	if constraint.Inside != "" {
+		return c.parseInside(solver, constraint)
+	}
+
 	target, ok := c.children[constraint.Target]
 	if !ok {
 		return fmt.Errorf("unknown target: '%s'", constraint.Target)
@@ -264,27 +638,19 @@ func (c *constraintLayout) parseConstraint(solver *casso.Solver, constraint Cons
 		return nil
 	}
 
+	src := constraintSource{ORIGIN_LAYOUT, constraint.String()}
 	terms := getAttributeTerms(constraint.TargetAttribute, target.getCelem(), -1.0) // constraint.Multiplier)
+	if constraint.Var != "" {
+		terms = append(terms, term{c.variable(constraint.Var).sym, 1})
+	}
 
 	if constraint.Source == "" {
-		_, err := solver.AddConstraintWithPriority(casso.Priority(constraint.Strength), casso.NewConstraint(casso.Op(constraint.Relation), constraint.Constant, terms...))
-		return err
+		return c.add(solver, src, casso.Priority(constraint.Strength), newLinear(casso.Op(constraint.Relation), constraint.Constant, terms...))
 	}
 
 	if constraint.Source == "super" {
-		var sourceVal float64
-
-		switch constraint.SourceAttribute {
-		case WIDTH, XEND:
-			sourceVal = float64(c.width)
-		case HEIGHT, YEND:
-			sourceVal = float64(c.height)
-		case XSTART, YSTART:
-			sourceVal = 0
-		}
-
-		_, err := solver.AddConstraintWithPriority(casso.Priority(constraint.Strength), casso.NewConstraint(casso.Op(constraint.Relation), sourceVal*constraint.Multiplier+constraint.Constant, terms...))
-		return err
+		terms = append(terms, getAttributeTerms(constraint.SourceAttribute, c.super, constraint.Multiplier)...)
+		return c.add(solver, src, casso.Priority(constraint.Strength), newLinear(casso.Op(constraint.Relation), constraint.Constant, terms...))
 	}
 
 	source, ok := c.children[constraint.Source]
@@ -293,17 +659,244 @@ func (c *constraintLayout) parseConstraint(solver *casso.Solver, constraint Cons
 	}
 	terms = append(terms, getAttributeTerms(constraint.SourceAttribute, source.getCelem(), constraint.Multiplier)...)
 
-	_, err := solver.AddConstraintWithPriority(casso.Priority(constraint.Strength), casso.NewConstraint(casso.Op(constraint.Relation), constraint.Constant, terms...))
-	return err
+	return c.add(solver, src, casso.Priority(constraint.Strength), newLinear(casso.Op(constraint.Relation), constraint.Constant+c.gapFor(constraint), terms...))
 }
 
-func (c *constraintLayout) fromJson(p []byte) error {
-	err := json.Unmarshal(p, &c.constraints)
-	return err
+// gapFor returns the gap that has to be added to a constraint, that places
+// the start of a bar at the end of another one or the other way around
+func (c *constraintLayout) gapFor(constraint Constraint) float64 {
+	if c.gap == 0 || constraint.Multiplier != 1 || constraint.Source == constraint.Target {
+		return 0
+	}
+
+	switch {
+	case constraint.TargetAttribute == XSTART && constraint.SourceAttribute == XEND,
+		constraint.TargetAttribute == YSTART && constraint.SourceAttribute == YEND:
+		return float64(c.gap)
+	case constraint.TargetAttribute == XEND && constraint.SourceAttribute == XSTART,
+		constraint.TargetAttribute == YEND && constraint.SourceAttribute == YSTART:
+		return -float64(c.gap)
+	}
+
+	return 0
+}
+
+func (c *constraintLayout) parseNoOverlap(solver *casso.Solver, constraint Constraint) error {
+	names := []string{}
+	bars := []barChild{}
+	for _, name := range constraint.barNames() {
+		bar, ok := c.children[name]
+		if !ok {
+			return fmt.Errorf("unknown bar: '%s'", name)
+		}
+		if constraint.Axis == AXIS_AUTO && bar.isHidden() {
+			continue
+		}
+		names = append(names, name)
+		bars = append(bars, bar)
+	}
+
+	src := constraintSource{ORIGIN_LAYOUT, constraint.String()}
+	if constraint.Axis != AXIS_AUTO {
+		for i := 1; i < len(bars); i++ {
+			c.addBefore(solver, src, constraint.Axis, bars[i-1], bars[i])
+		}
+		return nil
+	}
+
+	for i := 0; i < len(bars); i++ {
+		for j := i + 1; j < len(bars); j++ {
+			choice, ok := c.apart[barPair{names[i], names[j]}]
+			if !ok {
+				continue
+			}
+			first, second := bars[i], bars[j]
+			if choice.swap {
+				first, second = second, first
+			}
+			c.addBefore(solver, src, choice.axis, first, second)
+		}
+	}
+
+	return nil
+}
+
+// barPair are two bars of a no_overlap constraint with AXIS_AUTO
+type barPair struct {
+	first  string
+	second string
+}
+
+// apartChoice is how the bars of a pair are separated
+type apartChoice struct {
+	axis LayoutAxis
+	// swap places the second bar before the first one
+	swap bool
+}
+
+// apartChoices are tried in this order, so the declared order
+// and the horizontal axis win on equal terms
+var apartChoices = []apartChoice{
+	{AXIS_HORIZONTAL, false},
+	{AXIS_VERTICAL, false},
+	{AXIS_HORIZONTAL, true},
+	{AXIS_VERTICAL, true},
+}
+
+// autoPairs returns the pairs of the shown bars of the no_overlap
+// constraints with AXIS_AUTO
+func (c *constraintLayout) autoPairs() []barPair {
+	ret := []barPair{}
+	for _, con := range c.constraints {
+		if con.Relation != NO_OVERLAP || con.Axis != AXIS_AUTO {
+			continue
+		}
+		names := []string{}
+		for _, name := range con.normalized().barNames() {
+			if bar, ok := c.children[name]; ok && !bar.isHidden() {
+				names = append(names, name)
+			}
+		}
+		for i := 0; i < len(names); i++ {
+			for j := i + 1; j < len(names); j++ {
+				ret = append(ret, barPair{names[i], names[j]})
+			}
+		}
+	}
+
+	return ret
+}
+
+// apartKey is what the separation of the pairs was chosen for
+type apartKey struct {
+	width  int
+	height int
+	pairs  string
+}
+
+// chooseApart decides for each pair of bars of the no_overlap constraints
+// with AXIS_AUTO, how the bars are separated. The pairs are decided one
+// after another and every choice is tried with its own solver. The choice,
+// that misses the other constraints the least, is kept. The decision only
+// depends on the constraints, the shown bars and the size of the layout
+// and not on the previous solution, so it is kept until one of them changes
+func (c *constraintLayout) chooseApart() {
+	pairs := c.autoPairs()
+	w, h := c.innerSize()
+	key := apartKey{w, h, fmt.Sprint(pairs)}
+	if c.apart != nil && c.apartFor == key {
+		return
+	}
+
+	c.apart = map[barPair]apartChoice{}
+	c.apartFor = key
+	for _, pair := range pairs {
+		if _, ok := c.apart[pair]; ok {
+			continue
+		}
+		best := apartChoices[0]
+		cost := math.Inf(1)
+		for _, choice := range apartChoices {
+			c.apart[pair] = choice
+			if v := c.trialCost(); v < cost {
+				best = choice
+				cost = v
+			}
+		}
+		c.apart[pair] = best
+	}
+}
+
+// trialCost solves the layout with the current choices and returns the
+// misses of the non required constraints weighted by their strength.
+// Rejected constraints make the cost infinite
+func (c *constraintLayout) trialCost() float64 {
+	explained, applied, unsatisfied := c.explained, c.applied, c.unsatisfied
+	defer func() {
+		c.explained, c.applied, c.unsatisfied = explained, applied, unsatisfied
+	}()
+
+	solver := casso.NewSolver()
+	c.explained = solver
+	c.applied = nil
+	c.addLayoutSize(solver)
+	c.applyConstraints(solver, false)
+	c.addVars(solver, false)
+
+	cost := 0.0
+	for _, a := range c.applied {
+		if a.rejected {
+			return math.Inf(1)
+		}
+		if a.priority >= casso.Required || a.variable != nil {
+			continue
+		}
+		cost += float64(a.priority) * a.miss(solver)
+	}
+
+	return cost
+}
+
+// addBefore places the first bar before the second one along the axis
+func (c *constraintLayout) addBefore(solver *casso.Solver, src constraintSource, axis LayoutAxis, first, second barChild) {
+	fe := first.getCelem()
+	se := second.getCelem()
+	gap := float64(c.gap)
+	if axis == AXIS_VERTICAL {
+		c.add(solver, src, casso.Required, newLinear(casso.LTE, gap, term{fe.ypos, 1}, term{fe.height, 1}, term{se.ypos, -1}))
+		return
+	}
+	c.add(solver, src, casso.Required, newLinear(casso.LTE, gap, term{fe.xpos, 1}, term{fe.width, 1}, term{se.xpos, -1}))
+}
+
+// isInside tells if the target is placed inside the container by a constraint
+func (c *constraintLayout) isInside(target, container string) bool {
+	for _, con := range c.constraints {
+		if barKey(con.Target) == target && barKey(con.Inside) == container {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *constraintLayout) parseInside(solver *casso.Solver, constraint Constraint) error {
+	target, ok := c.children[constraint.Target]
+	if !ok {
+		return fmt.Errorf("unknown target: '%s'", constraint.Target)
+	}
+	container, ok := c.children[constraint.Inside]
+	if !ok {
+		return fmt.Errorf("unknown container: '%s'", constraint.Inside)
+	}
+
+	te := target.getCelem()
+	ce := container.getCelem()
+	src := constraintSource{ORIGIN_LAYOUT, constraint.String()}
+	c.add(solver, src, casso.Required, newLinear(casso.GTE, 0, term{te.xpos, 1}, term{ce.xpos, -1}))
+	c.add(solver, src, casso.Required, newLinear(casso.GTE, 0, term{te.ypos, 1}, term{ce.ypos, -1}))
+	c.add(solver, src, casso.Required, newLinear(casso.LTE, 0, term{te.xpos, 1}, term{te.width, 1}, term{ce.xpos, -1}, term{ce.width, -1}))
+	c.add(solver, src, casso.Required, newLinear(casso.LTE, 0, term{te.ypos, 1}, term{te.height, 1}, term{ce.ypos, -1}, term{ce.height, -1}))
+
+	return nil
+}
+
+func (c *constraintLayout) setDocument(doc *layoutDocument) {
+	c.constraints = doc.Constraints
+	c.declareVars(doc.Constraints)
+	c.splits = nil
+	c.apart = nil
+	c.dirty = true
 }
 
 func newConstraintLayout(sourceConstraints ...Constraint) *constraintLayout {
 	ret := &constraintLayout{
+		super: constraintElement{
+			width:  casso.New(),
+			height: casso.New(),
+			xpos:   casso.New(),
+			ypos:   casso.New(),
+		},
 		failsMax: 50,
 		dirty:    true,
 	}
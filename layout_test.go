@@ -0,0 +1,216 @@
+package chocolate
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const autoHideLayout = `{
+	"bars": {
+		"header": {"canhide": true, "models": [{"name": "title", "text": "title"}]},
+		"body": {"models": [{"name": "text", "text": "text"}]}
+	},
+	"constraints": [
+		{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 3, "strength": "required"},
+		{"target": "header", "target_attribute": "width", "source": "super", "source_attribute": "width", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "body", "target_attribute": "ystart", "source": "header", "source_attribute": "yend", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "body", "target_attribute": "yend", "source": "super", "source_attribute": "yend", "relation": "eq", "multiplier": 1, "strength": "required"},
+		{"target": "body", "target_attribute": "width", "source": "super", "source_attribute": "width", "relation": "eq", "multiplier": 1, "strength": "required"}
+	]
+}`
+
+// autoHideMsgs runs the command returned by Update and collects the
+// AutoHideMsgs of it
+func autoHideMsgs(cmd tea.Cmd) []AutoHideMsg {
+	if cmd == nil {
+		return nil
+	}
+	ret := []AutoHideMsg{}
+	switch msg := cmd().(type) {
+	case AutoHideMsg:
+		ret = append(ret, msg)
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			ret = append(ret, autoHideMsgs(cmd)...)
+		}
+	}
+
+	return ret
+}
+
+func TestAutoHide(t *testing.T) {
+	tests := []struct {
+		name   string
+		extra  string
+		width  int
+		height int
+		hidden bool
+	}{
+		{name: "fits", width: 80, height: 30},
+		{
+			name:   "conflicting required constraints that fit",
+			extra:  `{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 4, "strength": "required"}`,
+			width:  80,
+			height: 30,
+		},
+		{name: "too small", width: 80, height: 2, hidden: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChocolate()
+			if err := c.FromJson([]byte(autoHideLayout)); err != nil {
+				t.Fatal(err)
+			}
+			if tt.extra != "" {
+				var con Constraint
+				if err := con.UnmarshalJSON([]byte(tt.extra)); err != nil {
+					t.Fatal(err)
+				}
+				c.AddConstraints(con)
+			}
+			c.Resize(tt.width, tt.height)
+
+			hidden, err := c.IsAutoHidden("header")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hidden != tt.hidden {
+				t.Errorf("header auto hidden = %v, want %v", hidden, tt.hidden)
+			}
+		})
+	}
+}
+
+func TestAutoHideMsgs(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(autoHideLayout)); err != nil {
+		t.Fatal(err)
+	}
+	callbacks := 0
+	c.OnAutoHide(func(AutoHideMsg) { callbacks++ })
+
+	steps := []struct {
+		width  int
+		height int
+		want   []AutoHideMsg
+	}{
+		{80, 30, nil},
+		{80, 2, []AutoHideMsg{{Hidden: []string{"header"}, Unhidden: []string{}}}},
+		{60, 2, nil},
+		{80, 30, []AutoHideMsg{{Hidden: []string{}, Unhidden: []string{"header"}}}},
+	}
+	for i, step := range steps {
+		c.Resize(step.width, step.height)
+		c.View()
+		got := autoHideMsgs(c.Update(nil))
+		if !slices.EqualFunc(got, step.want, func(a, b AutoHideMsg) bool {
+			return slices.Equal(a.Hidden, b.Hidden) && slices.Equal(a.Unhidden, b.Unhidden)
+		}) {
+			t.Errorf("step %d: got %v, want %v", i, got, step.want)
+		}
+	}
+	if callbacks != 2 {
+		t.Errorf("OnAutoHide called %d times, want 2", callbacks)
+	}
+}
+
+const noOverlapLayout = `{
+	"bars": {
+		"Left": {"models": [{"name": "left", "text": "left"}]},
+		"Right": {"models": [{"name": "right", "text": "right"}]}
+	},
+	"constraints": [
+		{"target": "left", "target_attribute": "width", "relation": "eq", "constant": 30, "strength": "strong"},
+		{"target": "right", "target_attribute": "width", "relation": "eq", "constant": 30, "strength": "strong"},
+		{"target": "left", "target_attribute": "height", "relation": "eq", "constant": 5, "strength": "strong"},
+		{"target": "right", "target_attribute": "height", "relation": "eq", "constant": 5, "strength": "strong"},
+		{"relation": "no_overlap", "bars": ["LEFT", "Right"]}
+	]
+}`
+
+func overlaps(a, b *chocolateBar) bool {
+	return a.xpos() < b.xend() && b.xpos() < a.xend() &&
+		a.ypos() < b.yend() && b.ypos() < a.yend()
+}
+
+func TestNoOverlapAuto(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		height   int
+		vertical bool
+	}{
+		{"side by side", 80, 20, false},
+		{"on top of each other", 40, 20, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChocolate()
+			if err := c.FromJson([]byte(noOverlapLayout)); err != nil {
+				t.Fatal(err)
+			}
+			c.Resize(tt.width, tt.height)
+			c.View()
+
+			left, right := c.bars["left"], c.bars["right"]
+			if overlaps(left, right) {
+				t.Fatalf("bars overlap: left %d,%d %dx%d right %d,%d %dx%d",
+					left.xpos(), left.ypos(), left.width(), left.height(),
+					right.xpos(), right.ypos(), right.width(), right.height())
+			}
+			if vertical := left.xpos() < right.xend() && right.xpos() < left.xend(); vertical != tt.vertical {
+				t.Errorf("bars placed vertically = %v, want %v", vertical, tt.vertical)
+			}
+			if left.width() != 30 || right.width() != 30 {
+				t.Errorf("widths = %d and %d, want 30", left.width(), right.width())
+			}
+		})
+	}
+}
+
+func TestNoOverlapChoiceIsCached(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(noOverlapLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(80, 20)
+	c.View()
+	apart := reflect.ValueOf(c.root.apart).UnsafePointer()
+	if len(c.root.apart) != 1 {
+		t.Fatalf("got %d choices, want 1", len(c.root.apart))
+	}
+
+	c.root.setDirty()
+	c.View()
+	if reflect.ValueOf(c.root.apart).UnsafePointer() != apart {
+		t.Error("choices were made again without a change")
+	}
+
+	c.Resize(40, 20)
+	if reflect.ValueOf(c.root.apart).UnsafePointer() == apart {
+		t.Error("choices weren't made again for the new size")
+	}
+	apart = reflect.ValueOf(c.root.apart).UnsafePointer()
+
+	c.AddConstraints(NewConstraint().WithTarget("left").WithTargetAttribute(XSTART).WithRelation(EQ))
+	c.View()
+	if reflect.ValueOf(c.root.apart).UnsafePointer() == apart {
+		t.Error("choices weren't made again for the new constraint")
+	}
+}
+
+func TestIsInsideMixedCase(t *testing.T) {
+	c := NewChocolate()
+	c.AddConstraints(NewInside("Dialog", "SCREEN"))
+	if !c.root.isInside("dialog", "screen") {
+		t.Error("dialog isn't inside screen")
+	}
+	if c.root.isInside("screen", "dialog") {
+		t.Error("screen is inside dialog")
+	}
+}
@@ -0,0 +1,311 @@
+package chocolate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// layoutVars are the named numbers of a layout file. They are declared in
+// the vars section or as top-level "$name" keys. The numeric fields of the
+// constraints and splits, the gap and the padding can use "$name" instead
+// of a number
+type layoutVars map[string]float64
+
+// with returns a copy of the vars overridden by the other vars
+func (lv layoutVars) with(other map[string]float64) layoutVars {
+	ret := make(layoutVars, len(lv)+len(other))
+	for k, v := range lv {
+		ret[k] = v
+	}
+	for k, v := range other {
+		ret[strings.TrimPrefix(k, "$")] = v
+	}
+
+	return ret
+}
+
+// varFields are the fields of the objects of the sections, that can
+// use variables
+var varFields = map[string][]string{
+	"constraints": {"constant", "multiplier"},
+	"splits":      {"ratio", "min", "max"},
+}
+
+// substitute replaces the variables in the numeric fields of the section.
+// Values, that can't be decoded, are kept and reported when the document
+// is parsed
+func (lv layoutVars) substitute(section string, raw json.RawMessage) (json.RawMessage, error) {
+	section = strings.ToLower(section)
+	switch section {
+	case "gap":
+		return lv.value(raw)
+	case "padding":
+		values := []json.RawMessage{}
+		if json.Unmarshal(raw, &values) != nil {
+			return raw, nil
+		}
+		for i, v := range values {
+			v, err := lv.value(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return json.Marshal(values)
+	}
+
+	fields, ok := varFields[section]
+	if !ok {
+		return raw, nil
+	}
+	objects := []map[string]json.RawMessage{}
+	if json.Unmarshal(raw, &objects) != nil {
+		return raw, nil
+	}
+	for _, obj := range objects {
+		for key, v := range obj {
+			if !slices.Contains(fields, strings.ToLower(key)) {
+				continue
+			}
+			v, err := lv.value(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+	}
+
+	return json.Marshal(objects)
+}
+
+// value returns the number of the variable, if the value is a string of
+// the form "$name". Other values are returned as they are
+func (lv layoutVars) value(raw json.RawMessage) (json.RawMessage, error) {
+	var ref string
+	if json.Unmarshal(raw, &ref) != nil || !strings.HasPrefix(ref, "$") {
+		return raw, nil
+	}
+	val, ok := lv[ref[1:]]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable '%s'", ref)
+	}
+
+	return json.RawMessage(strconv.FormatFloat(val, 'g', -1, 64)), nil
+}
+
+// fragmentUse instantiates a fragment. The bars of the fragment are
+// prefixed and the variables override the defaults of the fragment.
+// Bars outside of the fragment are referenced with a leading "/"
+type fragmentUse struct {
+	Fragment string             `json:"fragment"`
+	Prefix   string             `json:"prefix"`
+	Vars     map[string]float64 `json:"vars"`
+}
+
+type layoutFragment struct {
+	raw json.RawMessage
+	dir string
+}
+
+// layoutLoader resolves includes, variables and fragments of layout files
+type layoutLoader struct {
+	files     []string
+	using     []string
+	loaded    []string
+	fragments map[string]layoutFragment
+}
+
+func newLayoutLoader() *layoutLoader {
+	return &layoutLoader{
+		fragments: make(map[string]layoutFragment),
+	}
+}
+
+func (l *layoutLoader) loadFile(file string, base layoutVars, override layoutVars) (*layoutDocument, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(l.files, abs) {
+		return nil, fmt.Errorf("include cycle: %s", strings.Join(append(l.files, abs), " -> "))
+	}
+
+	p, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(l.loaded, abs) {
+		l.loaded = append(l.loaded, abs)
+	}
+
+	l.files = append(l.files, abs)
+	defer func() { l.files = l.files[:len(l.files)-1] }()
+
+	doc, err := l.load(p, filepath.Dir(abs), base, override)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return doc, nil
+}
+
+// load parses the layout. The variables of the document override the base
+// variables and are overridden by the override variables
+func (l *layoutLoader) load(p []byte, dir string, base layoutVars, override layoutVars) (*layoutDocument, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(p), []byte("[")) {
+		return parseLayoutDocument(p)
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return nil, err
+	}
+
+	var (
+		includes  []string
+		vars      map[string]float64
+		fragments map[string]json.RawMessage
+		uses      []fragmentUse
+	)
+	for key, dst := range map[string]any{
+		"include":   &includes,
+		"vars":      &vars,
+		"fragments": &fragments,
+		"use":       &uses,
+	} {
+		if raw, ok := fields[key]; ok {
+			if err := json.Unmarshal(raw, dst); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			delete(fields, key)
+		}
+	}
+	for key, raw := range fields {
+		if !strings.HasPrefix(key, "$") {
+			continue
+		}
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if vars == nil {
+			vars = make(map[string]float64)
+		}
+		_, declared := vars[key[1:]]
+		if _, ok := vars[key]; ok || declared {
+			return nil, fmt.Errorf("variable '%s' is declared twice", key)
+		}
+		vars[key[1:]] = v
+		delete(fields, key)
+	}
+	scope := base.with(vars).with(override)
+
+	ret := &layoutDocument{}
+	for _, inc := range includes {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(dir, inc)
+		}
+		doc, err := l.loadFile(inc, nil, scope)
+		if err != nil {
+			return nil, err
+		}
+		ret.merge(doc)
+		ret.provide(doc)
+	}
+
+	for name, raw := range fragments {
+		l.fragments[strings.ToLower(name)] = layoutFragment{
+			raw: raw,
+			dir: dir,
+		}
+	}
+
+	for _, use := range uses {
+		doc, err := l.use(use, scope)
+		if err != nil {
+			return nil, err
+		}
+		ret.merge(doc)
+		ret.provide(doc)
+	}
+
+	// the remaining fields are joined without decoding them
+	// to keep the order of the bars
+	var raw bytes.Buffer
+	raw.WriteByte('{')
+	for key, value := range fields {
+		if raw.Len() > 1 {
+			raw.WriteByte(',')
+		}
+		value, err := scope.substitute(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		k, _ := json.Marshal(key)
+		raw.Write(k)
+		raw.WriteByte(':')
+		raw.Write(value)
+	}
+	raw.WriteByte('}')
+	own, err := parseLayoutDocument(raw.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ret.merge(own)
+	ret.declared = len(own.Bars) > 0
+
+	return ret, nil
+}
+
+func (l *layoutLoader) use(use fragmentUse, scope layoutVars) (*layoutDocument, error) {
+	name := strings.ToLower(use.Fragment)
+	fragment, ok := l.fragments[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fragment '%s'", use.Fragment)
+	}
+	if slices.Contains(l.using, name) {
+		return nil, fmt.Errorf("fragment cycle: %s", strings.Join(append(l.using, name), " -> "))
+	}
+
+	l.using = append(l.using, name)
+	defer func() { l.using = l.using[:len(l.using)-1] }()
+
+	doc, err := l.load(fragment.raw, fragment.dir, scope, layoutVars{}.with(use.Vars))
+	if err != nil {
+		return nil, fmt.Errorf("fragment '%s': %w", use.Fragment, err)
+	}
+	doc.prefix(strings.ToLower(use.Prefix))
+
+	return doc, nil
+}
+
+// loadLayout resolves the layout. Relative includes are resolved
+// against the directory
+func loadLayout(p []byte, dir string) (*layoutDocument, error) {
+	doc, err := newLayoutLoader().load(p, dir, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	doc.resolveOuter()
+
+	return doc, nil
+}
+
+// loadLayoutFile resolves the layout file and returns all files
+// that were read to resolve it
+func loadLayoutFile(file string) (*layoutDocument, []string, error) {
+	l := newLayoutLoader()
+	doc, err := l.loadFile(file, nil, nil)
+	if err != nil {
+		return nil, l.loaded, err
+	}
+	doc.resolveOuter()
+
+	return doc, l.loaded, nil
+}
@@ -0,0 +1,126 @@
+package chocolate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeLayoutFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestLoadLayoutFile(t *testing.T) {
+	dir := writeLayoutFiles(t, map[string]string{
+		"base.cnf": `{
+			"vars": {"header_height": 3},
+			"bars": {"header": {}},
+			"constraints": [{"target": "header", "target_attribute": "height", "relation": "eq", "constant": "$header_height"}]
+		}`,
+		"layout.cnf": `{
+			"include": ["base.cnf"],
+			"$header_height": 5,
+			"$ratio": 0.5,
+			"fragments": {
+				"dialog": {
+					"vars": {"width": 10},
+					"bars": {"body": {}},
+					"constraints": [
+						{"target": "body", "target_attribute": "width", "relation": "eq", "constant": "$width"},
+						{"target": "body", "target_attribute": "ystart", "source": "/header", "source_attribute": "yend", "relation": "eq", "multiplier": "$ratio"}
+					]
+				}
+			},
+			"use": [
+				{"fragment": "dialog", "prefix": "a_"},
+				{"fragment": "Dialog", "prefix": "b_", "vars": {"width": 20}}
+			]
+		}`,
+	})
+
+	doc, files, err := loadLayoutFile(filepath.Join(dir, "layout.cnf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("got %d loaded files, want 2", len(files))
+	}
+
+	got := []string{}
+	for _, con := range doc.Constraints {
+		got = append(got, con.String())
+	}
+	want := []string{
+		"header.height = 5 (medium)",
+		"a_body.width = 10 (medium)",
+		"a_body.ystart = 0.5 * header.yend (medium)",
+		"b_body.width = 20 (medium)",
+		"b_body.ystart = 0.5 * header.yend (medium)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("constraints:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	bars, _ := doc.names()
+	slices.Sort(bars)
+	if want := []string{"a_body", "b_body", "header"}; !slices.Equal(bars, want) {
+		t.Errorf("bars = %v, want %v", bars, want)
+	}
+}
+
+func TestLoadLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		substr string
+	}{
+		{
+			name:   "unknown variable",
+			files:  map[string]string{"layout.cnf": `{"constraints": [{"target": "a", "target_attribute": "width", "relation": "eq", "constant": "$nope"}]}`},
+			substr: "unknown variable '$nope'",
+		},
+		{
+			name:   "variable declared twice",
+			files:  map[string]string{"layout.cnf": `{"vars": {"width": 1}, "$width": 2}`},
+			substr: "variable '$width' is declared twice",
+		},
+		{
+			name:   "variable that isn't a number",
+			files:  map[string]string{"layout.cnf": `{"$width": "wide"}`},
+			substr: "$width",
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"layout.cnf": `{"include": ["other.cnf"]}`,
+				"other.cnf":  `{"include": ["layout.cnf"]}`,
+			},
+			substr: "include cycle",
+		},
+		{
+			name:   "unknown fragment",
+			files:  map[string]string{"layout.cnf": `{"use": [{"fragment": "nope"}]}`},
+			substr: "unknown fragment 'nope'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeLayoutFiles(t, tt.files)
+			_, _, err := loadLayoutFile(filepath.Join(dir, "layout.cnf"))
+			if err == nil || !strings.Contains(err.Error(), tt.substr) {
+				t.Errorf("got %v, want an error containing %q", err, tt.substr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package chocolate
+
+import (
+	"strings"
+)
+
+// GetModel returns the model of the bar, if it is of the type T.
+// Models added as tea.Model are returned as the tea.Model.
+// The bar can be a path into nested chocolates, that alternates between
+// bars and the names of the chocolate models in them, like
+// "sidebar/inner/menu" for the bar menu of the chocolate inner in the
+// bar sidebar
+func GetModel[T any](c *Chocolate, bar string, name string) (T, bool) {
+	var zero T
+	b, ok := c.lookupBar(bar)
+	if !ok {
+		return zero, false
+	}
+	model, ok := b.models[strings.ToLower(name)]
+	if !ok {
+		return zero, false
+	}
+
+	src := model.source()
+	if tm, ok := src.(*teaModel); ok {
+		src = tm.Model
+	}
+	ret, ok := src.(T)
+
+	return ret, ok
+}
+
+// lookupBar returns the bar for the path
+func (c *Chocolate) lookupBar(path string) (*chocolateBar, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments)%2 == 0 {
+		return nil, false
+	}
+
+	for len(segments) > 1 {
+		b, ok := c.bars[barKey(segments[0])]
+		if !ok {
+			return nil, false
+		}
+		model, ok := b.models[strings.ToLower(segments[1])]
+		if !ok {
+			return nil, false
+		}
+		if c, ok = model.source().(*Chocolate); !ok {
+			return nil, false
+		}
+		segments = segments[2:]
+	}
+
+	b, ok := c.bars[barKey(segments[0])]
+	return b, ok
+}
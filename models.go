@@ -18,11 +18,42 @@ type chocolateBarModel[T any] struct {
 	current  *lipgloss.Style
 	selected FlavourStyleSelector
 
+	overflow    OverflowPolicy
+	ownOverflow bool
+
+	labels map[FlavourStyleSelector]*borderLabels
+
 	srcModel T
 }
 
 func (cbm *chocolateBarModel[T]) setBar(v *chocolateBar) { cbm.bar = v }
 func (cbm *chocolateBarModel[T]) model() T               { return cbm.srcModel }
+func (cbm *chocolateBarModel[T]) source() any            { return cbm.srcModel }
+func (cbm *chocolateBarModel[T]) currentStyle() lipgloss.Style {
+	if cbm.current == nil {
+		return lipgloss.NewStyle()
+	}
+	return *cbm.current
+}
+func (cbm *chocolateBarModel[T]) selectedStyle() FlavourStyleSelector {
+	return cbm.selected
+}
+
+func (cbm *chocolateBarModel[T]) frameSize() (int, int) {
+	if cbm.current == nil {
+		return 0, 0
+	}
+	return cbm.current.GetHorizontalFrameSize(), cbm.current.GetVerticalFrameSize()
+}
+
+func (cbm *chocolateBarModel[T]) contentOffset() (int, int) {
+	if cbm.current == nil {
+		return 0, 0
+	}
+	s := cbm.current
+	return s.GetMarginLeft() + s.GetBorderLeftSize() + s.GetPaddingLeft(),
+		s.GetMarginTop() + s.GetBorderTopSize() + s.GetPaddingTop()
+}
 
 func (cbm *chocolateBarModel[T]) setDirty() {
 	if cbm.bar != nil {
@@ -30,6 +61,81 @@ func (cbm *chocolateBarModel[T]) setDirty() {
 	}
 }
 
+func (cbm *chocolateBarModel[T]) setOverflow(v OverflowPolicy, own bool) {
+	if cbm.ownOverflow && !own {
+		return
+	}
+	cbm.ownOverflow = own
+	if cbm.overflow == v {
+		return
+	}
+	cbm.overflow = v
+	if r, ok := cbm.barRenderer.(overflowSetter); ok {
+		r.setOverflow(v)
+	}
+	if c, ok := cbm.barConstrainer.(overflowSetter); ok {
+		c.setOverflow(v)
+	}
+	cbm.setDirty()
+}
+
+func (cbm *chocolateBarModel[T]) hintError() error {
+	if c, ok := cbm.barConstrainer.(hintChecker); ok {
+		return c.hintError()
+	}
+	return nil
+}
+
+func (cbm *chocolateBarModel[T]) setLabel(style FlavourStyleSelector, label *borderLabel, footer bool) {
+	cbm.labels = setLabel(cbm.labels, style, label, footer)
+	cbm.setDirty()
+}
+
+// borderLabels returns the title and footer of the selected style.
+// Labels of the model take precedence over the labels of the bar
+func (cbm *chocolateBarModel[T]) borderLabels() (title *borderLabel, footer *borderLabel) {
+	title, footer = getLabels(cbm.labels, cbm.selected)
+	if cbm.bar != nil {
+		bt, bf := getLabels(cbm.bar.labels, cbm.selected)
+		if title == nil {
+			title = bt
+		}
+		if footer == nil {
+			footer = bf
+		}
+	}
+
+	return title, footer
+}
+
+// render draws the titles and footers into the border after rendering
+func (cbm *chocolateBarModel[T]) render() string {
+	ret := cbm.barRenderer.render()
+	sr, ok := cbm.barRenderer.(styledRenderer)
+	if !ok {
+		return ret
+	}
+
+	title, footer := cbm.borderLabels()
+	if title == nil && footer == nil {
+		return ret
+	}
+
+	style := sr.getStyle()
+	ret = drawLabel(ret, style, title, false)
+	return drawLabel(ret, style, footer, true)
+}
+
+func (cbm *chocolateBarModel[T]) setBorderless(v bool) {
+	if r, ok := cbm.barRenderer.(borderlessSetter); ok {
+		r.setBorderless(v)
+	}
+	if c, ok := cbm.barConstrainer.(borderlessSetter); ok {
+		c.setBorderless(v)
+	}
+	cbm.setDirty()
+}
+
 func (cbm *chocolateBarModel[T]) selectStyle(style FlavourStyleSelector) {
 	s := FlavourStyleSelector(strings.ToLower(string(style)))
 	if sel, ok := cbm.styles[s]; ok {
@@ -70,6 +176,32 @@ func (cbm *chocolateBarModel[T]) addThemeModifier(style FlavourStyleSelector, mo
 	}
 }
 
+// setStyle replaces the style of a model, that isn't flavoured
+func (cbm *chocolateBarModel[T]) setStyle(style lipgloss.Style) {
+	r, ok := cbm.barRenderer.(*styleRenderer)
+	if !ok || len(cbm.styles) > 0 || r.style == nil {
+		return
+	}
+	*r.style = style
+	cbm.setDirty()
+}
+
+// setThemeModifiers replaces the modifiers of the style
+func (cbm *chocolateBarModel[T]) setThemeModifiers(style FlavourStyleSelector, modifiers ...ThemeStyleModifier) {
+	s := FlavourStyleSelector(strings.ToLower(string(style)))
+	if _, ok := cbm.styles[s]; !ok {
+		return
+	}
+	if cbm.styleModifier == nil {
+		cbm.styleModifier = make(map[FlavourStyleSelector][]ThemeStyleModifier)
+	}
+	cbm.styleModifier[s] = modifiers
+	if cbm.selected == s {
+		cbm.setDirty()
+		cbm.selectStyle(s)
+	}
+}
+
 func newChocolateBarModel[T any](
 	model T,
 	constrainer barConstrainer,
@@ -165,7 +297,7 @@ func newViewBarModel[T BarViewer](model T) *chocolateBarModel[T] {
 	vr := newViewRenderer(model, nil)
 	ret := newChocolateBarModel(
 		model,
-		newStyledConstrainer(nil, vr.content),
+		newStyledConstrainer(nil, vr.content).withHints(model),
 		vr,
 		nil, nil, "",
 	)
@@ -178,7 +310,7 @@ func newStyledViewBarModel[T BarViewer](model T, style *lipgloss.Style) *chocola
 	vr := newViewRenderer(model, style)
 	ret := newChocolateBarModel(
 		model,
-		newStyledConstrainer(style, vr.content),
+		newStyledConstrainer(style, vr.content).withHints(model),
 		vr,
 		nil, nil, "",
 	)
@@ -192,7 +324,7 @@ func newFlavouredViewBarModel[T BarViewer](model T, flavour *chocolateFlavour, s
 	vr := newViewRenderer(model, c)
 	ret := newChocolateBarModel(
 		model,
-		newStyledConstrainer(c, vr.content),
+		newStyledConstrainer(c, vr.content).withHints(model),
 		vr,
 		s, c, sel,
 	)
@@ -204,7 +336,7 @@ func newFlavouredViewBarModel[T BarViewer](model T, flavour *chocolateFlavour, s
 func newModelBarModel[T BarModel](model T) *chocolateBarModel[T] {
 	return newChocolateBarModel(
 		model,
-		newStyledConstrainer(nil),
+		newStyledConstrainer(nil).withHints(model),
 		newModelRenderer(model, nil),
 		nil, nil, "",
 	)
@@ -213,7 +345,7 @@ func newModelBarModel[T BarModel](model T) *chocolateBarModel[T] {
 func newStyledModelBarModel[T BarModel](model T, style *lipgloss.Style) *chocolateBarModel[T] {
 	return newChocolateBarModel(
 		model,
-		newStyledConstrainer(style),
+		newStyledConstrainer(style).withHints(model),
 		newModelRenderer(model, style),
 		nil, nil, "",
 	)
@@ -223,7 +355,7 @@ func newFlavouredModelBarModel[T BarModel](model T, flavour *chocolateFlavour, s
 	s, c, sel := flavour.getStyles(styles...)
 	return newChocolateBarModel(
 		model,
-		newStyledConstrainer(c),
+		newStyledConstrainer(c).withHints(model),
 		newModelRenderer(model, c),
 		s, c, sel,
 	)
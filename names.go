@@ -0,0 +1,69 @@
+package chocolate
+
+import (
+	"slices"
+	"strings"
+)
+
+// LayoutNames returns the names of all bars used by the layout file and the
+// names of the models declared in it per bar. The names are lower case like
+// the chocolate keys its bars and models. Includes and fragments are
+// resolved, but no models are created
+func LayoutNames(file string) (bars []string, models map[string][]string, err error) {
+	doc, _, err := loadLayoutFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bars, models = doc.names()
+	return bars, models, nil
+}
+
+// provide adds the bars used by the included or instantiated document
+// to the bars, that are declared
+func (ld *layoutDocument) provide(other *layoutDocument) {
+	bars, _ := other.names()
+	ld.provided = append(ld.provided, bars...)
+}
+
+func (ld *layoutDocument) names() (bars []string, models map[string][]string) {
+	bars = []string{}
+	models = map[string][]string{}
+	addBar := func(name string) {
+		name = barKey(name)
+		if name != "" && name != "super" && !slices.Contains(bars, name) {
+			bars = append(bars, name)
+		}
+	}
+	addModel := func(bar string, md modelDocument) {
+		addBar(bar)
+		bar = barKey(bar)
+		name := strings.ToLower(md.Name)
+		if name != "" && !slices.Contains(models[bar], name) {
+			models[bar] = append(models[bar], name)
+		}
+	}
+
+	for _, bar := range ld.Bars {
+		addBar(bar.name)
+		for _, md := range bar.Models {
+			addModel(bar.name, md)
+		}
+	}
+	for _, md := range ld.Models {
+		addModel(md.Bar, md.modelDocument)
+	}
+	for _, con := range ld.Constraints {
+		for _, name := range con.barNames() {
+			addBar(name)
+		}
+		addBar(con.Source)
+		addBar(con.Inside)
+	}
+	for _, split := range ld.Splits {
+		addBar(split.First)
+		addBar(split.Second)
+	}
+
+	return bars, models
+}
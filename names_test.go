@@ -0,0 +1,36 @@
+package chocolate
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLayoutNamesMixedCase(t *testing.T) {
+	dir := writeLayoutFiles(t, map[string]string{
+		"layout.cnf": `{
+			"bars": {"Header": {"models": [{"name": "Title", "text": "title"}]}, "Menu": {}, "Content": {}},
+			"models": [{"bar": "HEADER", "name": "status", "text": "status"}],
+			"constraints": [
+				{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 3},
+				{"target": "Content", "target_attribute": "ystart", "source": "HEADER", "source_attribute": "yend", "relation": "eq"},
+				{"target": "Menu", "target_attribute": "ystart", "source": "Super", "source_attribute": "ystart", "relation": "eq"}
+			],
+			"splits": [{"name": "sidebar", "first": "MENU", "second": "content"}]
+		}`,
+	})
+
+	bars, models, err := LayoutNames(filepath.Join(dir, "layout.cnf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"header", "menu", "content"}; !slices.Equal(bars, want) {
+		t.Errorf("bars = %v, want %v", bars, want)
+	}
+	if want := []string{"title", "status"}; !slices.Equal(models["header"], want) {
+		t.Errorf("models of header = %v, want %v", models["header"], want)
+	}
+	if len(models) != 1 {
+		t.Errorf("got models of %d bars, want 1", len(models))
+	}
+}
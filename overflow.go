@@ -0,0 +1,120 @@
+package chocolate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// OverflowPolicy defines how content that does not fit into the
+// resolved size of a bar is handled
+type OverflowPolicy uint8
+
+const (
+	// OVERFLOW_NONE keeps the content as it is. The bar will request
+	// enough space from the layout to show the whole content
+	OVERFLOW_NONE OverflowPolicy = iota
+	// OVERFLOW_CLIP cuts off everything right and below the bar
+	OVERFLOW_CLIP
+	// OVERFLOW_ELLIPSIS cuts off like OVERFLOW_CLIP but marks cut lines with an ellipsis
+	OVERFLOW_ELLIPSIS
+	// OVERFLOW_WRAP wraps long lines and cuts off the rows below the bar
+	OVERFLOW_WRAP
+	// OVERFLOW_SCROLL keeps the last rows visible like a log would do
+	OVERFLOW_SCROLL
+)
+
+const ellipsis = "…"
+
+func (op *OverflowPolicy) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch strings.ToUpper(v) {
+	case "NONE":
+		*op = OVERFLOW_NONE
+	case "CLIP":
+		*op = OVERFLOW_CLIP
+	case "ELLIPSIS":
+		*op = OVERFLOW_ELLIPSIS
+	case "WRAP":
+		*op = OVERFLOW_WRAP
+	case "SCROLL":
+		*op = OVERFLOW_SCROLL
+	default:
+		return fmt.Errorf("unknown overflow policy '%s'", v)
+	}
+
+	return nil
+}
+
+type overflowSetter interface {
+	setOverflow(OverflowPolicy)
+}
+
+// fitContent applies the overflow policy to the content so it will fit
+// into width x height cells. A width or height below 1 means the size
+// is not known yet and the content is returned untouched
+func fitContent(s string, width, height int, policy OverflowPolicy) string {
+	if width < 1 || height < 1 {
+		return s
+	}
+
+	switch policy {
+	case OVERFLOW_CLIP:
+		return clipBlock(s, width, height)
+	case OVERFLOW_ELLIPSIS:
+		lines := strings.Split(s, "\n")
+		cut := len(lines) > height
+		if cut {
+			lines = lines[:height]
+		}
+		for i, l := range lines {
+			lines[i] = truncate.StringWithTail(l, uint(width), ellipsis)
+		}
+		if last := len(lines) - 1; cut && last >= 0 {
+			if lipgloss.Width(lines[last]) >= width {
+				lines[last] = truncate.StringWithTail(lines[last], uint(width-1), "") + ellipsis
+			} else {
+				lines[last] += ellipsis
+			}
+		}
+		return strings.Join(lines, "\n")
+	case OVERFLOW_WRAP:
+		return clipBlock(wrap.String(wordwrap.String(s, width), width), width, height)
+	case OVERFLOW_SCROLL:
+		lines := strings.Split(s, "\n")
+		if len(lines) > height {
+			lines = lines[len(lines)-height:]
+		}
+		return clipBlock(strings.Join(lines, "\n"), width, height)
+	}
+
+	return s
+}
+
+// clipBlock cuts the content ANSI-safe to at most width x height cells
+func clipBlock(s string, width, height int) string {
+	if width < 1 || height < 1 {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, l := range lines {
+		if lipgloss.Width(l) > width {
+			lines[i] = truncate.String(l, uint(width))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
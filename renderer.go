@@ -6,19 +6,28 @@ import (
 )
 
 type chocolateBarRenderer struct {
-	width   int
-	height  int
-	content *string
+	width    int
+	height   int
+	content  *string
+	overflow OverflowPolicy
 }
 
-func (cbr *chocolateBarRenderer) setSize(width, height int) { cbr.width = width; cbr.height = height }
-func (cbr *chocolateBarRenderer) render() string {
+func (cbr *chocolateBarRenderer) setSize(width, height int)    { cbr.width = width; cbr.height = height }
+func (cbr *chocolateBarRenderer) setOverflow(v OverflowPolicy) { cbr.overflow = v }
+func (cbr *chocolateBarRenderer) raw() string {
 	if cbr.content != nil {
 		return *cbr.content
 	}
 	return ""
 }
 
+func (cbr *chocolateBarRenderer) render() string {
+	return clipBlock(
+		fitContent(cbr.raw(), cbr.width, cbr.height, cbr.overflow),
+		cbr.width, cbr.height,
+	)
+}
+
 type noneRenderer struct {
 	chocolateBarRenderer
 }
@@ -41,16 +50,22 @@ type styleRenderer struct {
 	chocolateBarRenderer
 	style        *lipgloss.Style
 	defaultStyle lipgloss.Style
+	borderless   bool
 	cwidth       int
 	cheight      int
 }
 
-func (sr *styleRenderer) getStyle() *lipgloss.Style {
+func (sr *styleRenderer) setBorderless(v bool) { sr.borderless = v }
+func (sr *styleRenderer) getStyle() lipgloss.Style {
+	ret := sr.defaultStyle
 	if sr.style != nil {
-		return sr.style
+		ret = *sr.style
+	}
+	if sr.borderless {
+		ret = withoutBorder(ret)
 	}
 
-	return &sr.defaultStyle
+	return ret
 }
 
 func (sr *styleRenderer) setSize(width, height int) {
@@ -62,10 +77,12 @@ func (sr *styleRenderer) setSize(width, height int) {
 
 func (sr *styleRenderer) render() string {
 	sr.setSize(sr.width, sr.height)
-	return sr.getStyle().
+	ret := sr.getStyle().
 		Width(sr.cwidth).
 		Height(sr.cheight).
-		Render(sr.chocolateBarRenderer.render())
+		Render(fitContent(sr.raw(), sr.cwidth, sr.cheight, sr.overflow))
+
+	return clipBlock(ret, sr.width, sr.height)
 }
 
 func newStyleRenderer(content *string, style *lipgloss.Style) *styleRenderer {
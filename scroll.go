@@ -0,0 +1,315 @@
+package chocolate
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	scrollTrack = "│"
+	scrollThumb = "┃"
+)
+
+// ScrollModel wraps any BarViewer or BarModel and shows only the part of
+// its content, that fits into the bar. It implements BarModel and can be
+// placed with AddModelBarModel like any other model.
+type ScrollModel struct {
+	viewer BarViewer
+	model  BarModel
+
+	keys       *ScrollKeyMap
+	wheelDelta int
+
+	width   int
+	height  int
+	yoffset int
+	xoffset int
+	lines   int
+	// width of the widest line
+	columns int
+	// width the model was resized to
+	modelWidth int
+
+	scrollbar  bool
+	trackStyle lipgloss.Style
+	thumbStyle lipgloss.Style
+}
+
+func (sm *ScrollModel) YOffset() int { return sm.yoffset }
+func (sm *ScrollModel) XOffset() int { return sm.xoffset }
+func (sm *ScrollModel) AtTop() bool  { return sm.yoffset <= 0 }
+func (sm *ScrollModel) AtBottom() bool {
+	return sm.yoffset >= sm.maxYOffset()
+}
+
+func (sm *ScrollModel) SetYOffset(v int) { sm.yoffset = clamp(v, 0, sm.maxYOffset()) }
+func (sm *ScrollModel) SetXOffset(v int) { sm.xoffset = clamp(v, 0, sm.maxXOffset()) }
+func (sm *ScrollModel) LineUp(n int)     { sm.SetYOffset(sm.yoffset - n) }
+func (sm *ScrollModel) LineDown(n int)   { sm.SetYOffset(sm.yoffset + n) }
+func (sm *ScrollModel) PageUp()          { sm.LineUp(max(sm.height, 1)) }
+func (sm *ScrollModel) PageDown()        { sm.LineDown(max(sm.height, 1)) }
+func (sm *ScrollModel) GotoTop()         { sm.yoffset = 0 }
+func (sm *ScrollModel) GotoBottom()      { sm.refresh(); sm.yoffset = sm.maxYOffset() }
+
+func (sm *ScrollModel) maxYOffset() int { return max(sm.lines-sm.height, 0) }
+func (sm *ScrollModel) maxXOffset() int { return max(sm.columns-sm.contentWidth(sm.lines), 0) }
+
+// showScrollbar tells if the scrollbar is drawn for content with
+// the number of lines
+func (sm *ScrollModel) showScrollbar(lines int) bool {
+	return sm.scrollbar && sm.height > 0 && lines > sm.height
+}
+
+// contentWidth returns the width left for content with the number of
+// lines. The scrollbar takes a column only, if it is drawn
+func (sm *ScrollModel) contentWidth(lines int) int {
+	if sm.showScrollbar(lines) {
+		return max(sm.width-1, 0)
+	}
+	return sm.width
+}
+
+func (sm *ScrollModel) content() []string {
+	if sm.viewer == nil {
+		return []string{""}
+	}
+	return strings.Split(sm.viewer.View(), "\n")
+}
+
+// refresh measures the content, resizes the model when the scrollbar
+// appears or disappears and keeps the offsets in range
+func (sm *ScrollModel) refresh() {
+	lines := sm.content()
+	if sm.model != nil && sm.modelWidth != sm.contentWidth(len(lines)) {
+		sm.modelWidth = sm.contentWidth(len(lines))
+		sm.model.Resize(sm.modelWidth, sm.height)
+		lines = sm.content()
+	}
+	sm.lines = len(lines)
+	sm.columns = widest(lines)
+	sm.SetYOffset(sm.yoffset)
+	sm.SetXOffset(sm.xoffset)
+}
+
+func widest(lines []string) int {
+	ret := 0
+	for _, line := range lines {
+		ret = max(ret, lipgloss.Width(line))
+	}
+	return ret
+}
+
+func (sm *ScrollModel) Resize(width, height int) {
+	sm.width = width
+	sm.height = height
+	sm.modelWidth = -1
+	sm.refresh()
+}
+
+// Update scrolls by the keys and the mouse wheel. All other messages
+// are passed on to the wrapped model, if it is a tea.Model
+func (sm *ScrollModel) Update(msg tea.Msg) (*ScrollModel, tea.Cmd) {
+	sm.refresh()
+	if sm.scroll(msg) {
+		return sm, nil
+	}
+
+	m, ok := sm.viewer.(tea.Model)
+	if !ok {
+		return sm, nil
+	}
+	next, cmd := m.Update(msg)
+	sm.viewer = next
+	if model, ok := next.(BarModel); ok {
+		sm.model = model
+	}
+	sm.refresh()
+
+	return sm, cmd
+}
+
+// scroll handles the message, if it scrolls the content
+func (sm *ScrollModel) scroll(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if sm.keys == nil {
+			return false
+		}
+		switch {
+		case key.Matches(msg, sm.keys.Up):
+			sm.LineUp(1)
+		case key.Matches(msg, sm.keys.Down):
+			sm.LineDown(1)
+		case key.Matches(msg, sm.keys.Left):
+			sm.SetXOffset(sm.xoffset - 1)
+		case key.Matches(msg, sm.keys.Right):
+			sm.SetXOffset(sm.xoffset + 1)
+		case key.Matches(msg, sm.keys.PageUp):
+			sm.PageUp()
+		case key.Matches(msg, sm.keys.PageDown):
+			sm.PageDown()
+		case key.Matches(msg, sm.keys.Top):
+			sm.GotoTop()
+		case key.Matches(msg, sm.keys.Bottom):
+			sm.GotoBottom()
+		default:
+			return false
+		}
+		return true
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return false
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			sm.LineUp(sm.wheelDelta)
+		case tea.MouseButtonWheelDown:
+			sm.LineDown(sm.wheelDelta)
+		case tea.MouseButtonWheelLeft:
+			sm.SetXOffset(sm.xoffset - sm.wheelDelta)
+		case tea.MouseButtonWheelRight:
+			sm.SetXOffset(sm.xoffset + sm.wheelDelta)
+		default:
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+// MinSize, PreferredSize and MaxSize pass the size hints of the wrapped
+// model on, if it implements SizeHinter
+func (sm *ScrollModel) MinSize() (width, height int) {
+	if h, ok := sm.viewer.(SizeHinter); ok {
+		return h.MinSize()
+	}
+	return 0, 0
+}
+
+func (sm *ScrollModel) PreferredSize() (width, height int) {
+	if h, ok := sm.viewer.(SizeHinter); ok {
+		return h.PreferredSize()
+	}
+	return 0, 0
+}
+
+func (sm *ScrollModel) MaxSize() (width, height int) {
+	if h, ok := sm.viewer.(SizeHinter); ok {
+		return h.MaxSize()
+	}
+	return 0, 0
+}
+
+// View renders the visible part of the content. The content may have
+// changed since the last Update, so the offsets are only limited here and
+// stored by the next Update or Resize
+func (sm *ScrollModel) View() string {
+	lines := sm.content()
+	if sm.height < 1 || sm.width < 1 {
+		return strings.Join(lines, "\n")
+	}
+
+	total := len(lines)
+	yoffset := clamp(sm.yoffset, 0, max(total-sm.height, 0))
+	cw := sm.contentWidth(total)
+	xoffset := clamp(sm.xoffset, 0, max(widest(lines)-cw, 0))
+	lines = lines[yoffset:min(yoffset+sm.height, total)]
+	bar := sm.showScrollbar(total)
+	thumbSize, thumbPos := sm.thumb(total, yoffset)
+
+	var b strings.Builder
+	for i := 0; i < sm.height; i++ {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+			if xoffset > 0 {
+				line = cutLeft(line, xoffset)
+			}
+			line = truncate.String(line, uint(cw))
+		}
+		if !bar {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString(strings.Repeat(" ", max(cw-lipgloss.Width(line), 0)))
+		if i >= thumbPos && i < thumbPos+thumbSize {
+			b.WriteString(sm.thumbStyle.Render(scrollThumb))
+		} else {
+			b.WriteString(sm.trackStyle.Render(scrollTrack))
+		}
+	}
+
+	return b.String()
+}
+
+func (sm *ScrollModel) thumb(lines, yoffset int) (size int, pos int) {
+	if lines <= sm.height || sm.height < 1 {
+		return sm.height, 0
+	}
+	size = max(sm.height*sm.height/lines, 1)
+	pos = (sm.height - size) * yoffset / (lines - sm.height)
+
+	return size, pos
+}
+
+type ScrollOption func(*ScrollModel)
+
+func WithScrollKeyMap(km *ScrollKeyMap) ScrollOption {
+	return func(sm *ScrollModel) {
+		sm.keys = km
+	}
+}
+
+func WithWheelDelta(v int) ScrollOption {
+	return func(sm *ScrollModel) {
+		sm.wheelDelta = v
+	}
+}
+
+func WithScrollbar(track, thumb lipgloss.Style) ScrollOption {
+	return func(sm *ScrollModel) {
+		sm.scrollbar = true
+		sm.trackStyle = track
+		sm.thumbStyle = thumb
+	}
+}
+
+func WithFlavouredScrollbar(flavour *chocolateFlavour) ScrollOption {
+	track := lipgloss.NewStyle()
+	thumb := lipgloss.NewStyle()
+	if s, ok := flavour.styles[TS_DEFAULT]; ok {
+		track = track.Foreground(s.GetForeground()).Background(s.GetBackground())
+		thumb = track
+	}
+	if s, ok := flavour.styles[TS_SELECTED]; ok {
+		thumb = thumb.Foreground(s.GetForeground()).Background(s.GetBackground())
+	}
+
+	return WithScrollbar(track, thumb)
+}
+
+func NewScrollModel(viewer BarViewer, opts ...ScrollOption) *ScrollModel {
+	ret := &ScrollModel{
+		viewer:     viewer,
+		keys:       DefaultScrollKeyMap(),
+		wheelDelta: 3,
+	}
+	if model, ok := viewer.(BarModel); ok {
+		ret.model = model
+	}
+
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	return ret
+}
@@ -0,0 +1,82 @@
+package chocolate
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// countingModel is a tea.Model with size hints, that counts its updates
+type countingModel struct {
+	updates int
+	lines   []string
+}
+
+func (m *countingModel) Init() tea.Cmd { return nil }
+func (m *countingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.updates++
+	return m, nil
+}
+func (m *countingModel) View() string                       { return strings.Join(m.lines, "\n") }
+func (m *countingModel) MinSize() (width, height int)       { return 5, 2 }
+func (m *countingModel) PreferredSize() (width, height int) { return 20, 10 }
+func (m *countingModel) MaxSize() (width, height int)       { return 40, 0 }
+
+func TestScrollModelForwardsMessages(t *testing.T) {
+	m := &countingModel{lines: []string{"a", "b", "c"}}
+	sm := NewScrollModel(m)
+	sm.Resize(10, 2)
+
+	sm.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.updates != 0 {
+		t.Errorf("scroll key was passed on")
+	}
+	if sm.YOffset() != 1 {
+		t.Errorf("YOffset = %d, want 1", sm.YOffset())
+	}
+
+	sm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	sm.Update(tea.FocusMsg{})
+	if m.updates != 2 {
+		t.Errorf("wrapped model got %d updates, want 2", m.updates)
+	}
+}
+
+func TestScrollModelXOffset(t *testing.T) {
+	sm := NewScrollModel(&countingModel{lines: []string{"0123456789", "01234"}})
+	sm.Resize(4, 2)
+
+	sm.SetXOffset(100)
+	if got := sm.XOffset(); got != 6 {
+		t.Errorf("XOffset = %d, want 6", got)
+	}
+	if got := sm.View(); got != "6789\n" {
+		t.Errorf("View = %q, want %q", got, "6789\n")
+	}
+	sm.SetXOffset(-1)
+	if got := sm.XOffset(); got != 0 {
+		t.Errorf("XOffset = %d, want 0", got)
+	}
+}
+
+func TestScrollModelSizeHints(t *testing.T) {
+	var hinter SizeHinter = NewScrollModel(&countingModel{})
+	if w, h := hinter.MinSize(); w != 5 || h != 2 {
+		t.Errorf("MinSize = %d, %d, want 5, 2", w, h)
+	}
+	if w, h := hinter.PreferredSize(); w != 20 || h != 10 {
+		t.Errorf("PreferredSize = %d, %d, want 20, 10", w, h)
+	}
+	if w, h := hinter.MaxSize(); w != 40 || h != 0 {
+		t.Errorf("MaxSize = %d, %d, want 40, 0", w, h)
+	}
+
+	if w, h := NewScrollModel(textViewer("text")).MinSize(); w != 0 || h != 0 {
+		t.Errorf("MinSize without hints = %d, %d, want 0, 0", w, h)
+	}
+}
+
+type textViewer string
+
+func (tv textViewer) View() string { return string(tv) }
@@ -0,0 +1,198 @@
+package chocolate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lithdew/casso"
+)
+
+// Split places two bars next to each other along the axis and shares
+// the space between them by a ratio, that can be changed while running.
+// Min and Max limit the size of the first bar in cells, where a Max
+// below 1 means no limit
+type Split struct {
+	Name   string     `json:"name"`
+	First  string     `json:"first"`
+	Second string     `json:"second"`
+	Axis   LayoutAxis `json:"axis"`
+	Ratio  float64    `json:"ratio"`
+	Min    int        `json:"min"`
+	Max    int        `json:"max"`
+}
+
+// layoutSplit is a split with its current ratio. The ratio is the
+// coefficient of the constraint sharing the space, so unlike the layout
+// variables it can't be an edit variable of the solver. Every change of
+// the ratio resolves the whole layout again
+type layoutSplit struct {
+	Split
+	ratio float64
+}
+
+func (ls *layoutSplit) vertical() bool { return ls.Axis == AXIS_VERTICAL }
+
+func (c *constraintLayout) addSplit(split Split) error {
+	name := strings.ToLower(split.Name)
+	if name == "" {
+		return fmt.Errorf("split without name")
+	}
+	if c.split(name) != nil {
+		return fmt.Errorf("split '%s' already exists", name)
+	}
+	split.Name = name
+	split.First = barKey(split.First)
+	split.Second = barKey(split.Second)
+	if split.Ratio <= 0 || split.Ratio >= 1 {
+		split.Ratio = 0.5
+	}
+
+	c.splits = append(c.splits, &layoutSplit{
+		Split: split,
+		ratio: split.Ratio,
+	})
+	c.apart = nil
+	c.setDirty()
+
+	return nil
+}
+
+func (c *constraintLayout) split(name string) *layoutSplit {
+	name = strings.ToLower(name)
+	for _, s := range c.splits {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// splitSizes returns the current sizes of both bars of the split along its axis
+func (c *constraintLayout) splitSizes(s *layoutSplit) (first, second int, ok bool) {
+	f, fok := c.children[s.First]
+	sec, sok := c.children[s.Second]
+	if !fok || !sok {
+		return 0, 0, false
+	}
+	if s.vertical() {
+		return f.height(), sec.height(), true
+	}
+	return f.width(), sec.width(), true
+}
+
+// adjustSplit moves the boundary of the split by delta cells
+func (c *constraintLayout) adjustSplit(name string, delta int) bool {
+	s := c.split(name)
+	if s == nil {
+		return false
+	}
+	first, _, ok := c.splitSizes(s)
+	if !ok {
+		return false
+	}
+
+	return c.resizeSplit(s, first+delta)
+}
+
+// resizeSplit sets the ratio of the split, so the first bar gets
+// size cells respecting the limits of the split
+func (c *constraintLayout) resizeSplit(s *layoutSplit, size int) bool {
+	first, second, ok := c.splitSizes(s)
+	total := first + second
+	if !ok || total < 1 {
+		return false
+	}
+
+	if s.Max > 0 {
+		size = min(size, s.Max)
+	}
+	size = clamp(size, min(s.Min, total), total)
+	ratio := float64(size) / float64(total)
+	if ratio == s.ratio {
+		return false
+	}
+	s.ratio = ratio
+	c.setDirty()
+
+	return true
+}
+
+func (c *constraintLayout) setSplitRatio(name string, ratio float64) bool {
+	s := c.split(name)
+	if s == nil {
+		return false
+	}
+	s.ratio = clamp01(ratio)
+	c.setDirty()
+
+	return true
+}
+
+// parseSplit adds the constraints of the split to the solver.
+// If minimal is set, only the required constraints are added
+func (c *constraintLayout) parseSplit(solver *casso.Solver, s *layoutSplit, minimal bool) error {
+	first, ok := c.children[s.First]
+	if !ok {
+		return fmt.Errorf("unknown bar: '%s'", s.First)
+	}
+	second, ok := c.children[s.Second]
+	if !ok {
+		return fmt.Errorf("unknown bar: '%s'", s.Second)
+	}
+
+	axis := AXIS_HORIZONTAL
+	if s.vertical() {
+		axis = AXIS_VERTICAL
+	}
+	fe := first.getCelem()
+	se := second.getCelem()
+	fsize, ssize, fstart, sstart := fe.width, se.width, fe.xpos, se.xpos
+	if axis == AXIS_VERTICAL {
+		fsize, ssize, fstart, sstart = fe.height, se.height, fe.ypos, se.ypos
+	}
+
+	gap := float64(c.gap)
+	src := constraintSource{ORIGIN_SPLIT, "split " + s.Name}
+	c.add(solver, src, casso.Required, newLinear(casso.EQ, gap, term{fstart, 1}, term{fsize, 1}, term{sstart, -1}))
+	if s.Min > 0 {
+		c.add(solver, src, casso.Required, symbolConstraint(fsize, casso.GTE, float64(s.Min)))
+	}
+	if s.Max > 0 {
+		c.add(solver, src, casso.Required, symbolConstraint(fsize, casso.LTE, float64(s.Max)))
+	}
+	if minimal {
+		return nil
+	}
+	c.add(solver, src, casso.Strong, newLinear(casso.EQ, 0, term{fsize, 1 - s.ratio}, term{ssize, -s.ratio}))
+
+	return nil
+}
+
+// splitAt returns the split, whose boundary is at the position
+func (c *constraintLayout) splitAt(x, y int) (*layoutSplit, bool) {
+	for _, s := range c.splits {
+		first, fok := c.children[s.First]
+		second, sok := c.children[s.Second]
+		if !fok || !sok || first.isHidden() || second.isHidden() {
+			continue
+		}
+		if s.vertical() {
+			if y >= first.yend()-1 && y <= second.ypos() &&
+				x >= min(first.xpos(), second.xpos()) && x < max(first.xend(), second.xend()) {
+				return s, true
+			}
+			continue
+		}
+		if x >= first.xend()-1 && x <= second.xpos() &&
+			y >= min(first.ypos(), second.ypos()) && y < max(first.yend(), second.yend()) {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+func clamp01(v float64) float64 {
+	return min(max(v, 0), 1)
+}
@@ -0,0 +1,114 @@
+package chocolate
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const splitLayout = `{
+	"bars": {
+		"Menu": {"models": [{"name": "menu", "text": "menu"}]},
+		"Content": {"models": [{"name": "text", "text": "text"}]}
+	},
+	"constraints": [
+		{"target": "menu", "target_attribute": "height", "source": "super", "source_attribute": "height", "relation": "eq", "strength": "required"},
+		{"target": "content", "target_attribute": "height", "source": "super", "source_attribute": "height", "relation": "eq", "strength": "required"},
+		{"target": "menu", "target_attribute": "xstart", "source": "super", "source_attribute": "xstart", "relation": "eq", "strength": "required"},
+		{"target": "content", "target_attribute": "xend", "source": "super", "source_attribute": "xend", "relation": "eq", "strength": "required"}
+	],
+	"splits": [{"name": "Sidebar", "first": "Menu", "second": "Content", "ratio": 0.25, "min": 5}]
+}`
+
+func newSplitChocolate(t *testing.T) *Chocolate {
+	t.Helper()
+	c := NewChocolate()
+	if err := c.FromJson([]byte(splitLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 10)
+	c.View()
+
+	return c
+}
+
+func menuWidth(c *Chocolate) int {
+	c.View()
+	return c.bars["menu"].width()
+}
+
+func TestSplit(t *testing.T) {
+	c := newSplitChocolate(t)
+	if got := len(c.Bars()); got != 2 {
+		t.Fatalf("split created new bars, got %d bars, want 2", got)
+	}
+	if got := menuWidth(c); got != 10 {
+		t.Fatalf("menu width = %d, want 10", got)
+	}
+	if got := c.bars["content"].width(); got != 30 {
+		t.Fatalf("content width = %d, want 30", got)
+	}
+
+	tests := []struct {
+		name  string
+		delta int
+		want  int
+	}{
+		{"grow", 2, 12},
+		{"shrink", -4, 8},
+		{"min", -20, 5},
+		{"content model", 100, 36},
+	}
+	for _, tt := range tests {
+		c.AdjustSplit("SIDEBAR", tt.delta)
+		if got := menuWidth(c); got != tt.want {
+			t.Errorf("%s: menu width = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	c := newSplitChocolate(t)
+	c.SetSplitKeyMap(DefaultSplitKeyMap())
+
+	grow := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")}
+	c.Update(grow)
+	if got := menuWidth(c); got != 10 {
+		t.Fatalf("keys resized the split without focus, menu width = %d", got)
+	}
+
+	if err := c.FocusSplit("sidebar"); err != nil {
+		t.Fatal(err)
+	}
+	c.Update(grow)
+	if got := menuWidth(c); got != 11 {
+		t.Errorf("menu width after grow = %d, want 11", got)
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	if got := menuWidth(c); got != 10 {
+		t.Errorf("menu width after shrink = %d, want 10", got)
+	}
+
+	if err := c.FocusSplit("nope"); err == nil {
+		t.Error("FocusSplit of an unknown split succeeded")
+	}
+}
+
+func TestAddSplit(t *testing.T) {
+	c := newSplitChocolate(t)
+	if err := c.AddSplit(Split{Name: "sidebar", First: "menu", Second: "content"}); err == nil {
+		t.Error("AddSplit of an existing split succeeded")
+	}
+	if err := c.AddSplit(Split{First: "menu", Second: "content"}); err == nil {
+		t.Error("AddSplit without name succeeded")
+	}
+	if err := c.AddSplit(Split{Name: "other", First: "MENU", Second: "Status"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(c.Bars()); got != 3 {
+		t.Errorf("got %d bars, want 3", got)
+	}
+	if !c.IsBar("status") {
+		t.Error("AddSplit didn't create the bar status")
+	}
+}
@@ -0,0 +1,158 @@
+package chocolate
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// chocolateState is the serialized form of everything, that the user can
+// change on a running layout
+type chocolateState struct {
+	RootStyle FlavourStyleSelector     `json:"root_style,omitempty"`
+	Bars      map[string]*barState     `json:"bars,omitempty"`
+	Overlays  map[string]*overlayState `json:"overlays,omitempty"`
+	Splits    map[string]float64       `json:"splits,omitempty"`
+	Vars      map[string]float64       `json:"vars,omitempty"`
+}
+
+type barState struct {
+	Hidden bool                            `json:"hidden,omitempty"`
+	Model  string                          `json:"model,omitempty"`
+	Styles map[string]FlavourStyleSelector `json:"styles,omitempty"`
+	Nested map[string]*chocolateState      `json:"nested,omitempty"`
+}
+
+type overlayState struct {
+	Enabled bool            `json:"enabled"`
+	State   *chocolateState `json:"state,omitempty"`
+}
+
+func (c *Chocolate) state() *chocolateState {
+	ret := &chocolateState{
+		RootStyle: c.rootModel.current.selectedStyle(),
+		Bars:      make(map[string]*barState),
+		Overlays:  make(map[string]*overlayState),
+		Splits:    make(map[string]float64),
+		Vars:      make(map[string]float64),
+	}
+
+	for name, b := range c.bars {
+		bs := &barState{
+			// bars hidden because of missing space are not saved
+			Hidden: b.isHidden() && !b.isAutoHidden(),
+			Model:  b.selectedName(),
+			Styles: make(map[string]FlavourStyleSelector),
+			Nested: make(map[string]*chocolateState),
+		}
+		for mname, model := range b.models {
+			if s := model.selectedStyle(); s != "" {
+				bs.Styles[mname] = s
+			}
+			if nested, ok := model.source().(*Chocolate); ok {
+				bs.Nested[mname] = nested.state()
+			}
+		}
+		ret.Bars[name] = bs
+	}
+
+	for name, o := range c.overlays {
+		ret.Overlays[name] = &overlayState{
+			Enabled: o.enabled,
+			State:   o.Chocolate.state(),
+		}
+	}
+
+	for _, s := range c.root.splits {
+		ret.Splits[s.Name] = s.ratio
+	}
+
+	for name, v := range c.root.vars {
+		ret.Vars[name] = v.value
+	}
+
+	return ret
+}
+
+// applyState restores the state. Bars, models, overlays, splits and
+// variables that don't exist (anymore) are ignored
+func (c *Chocolate) applyState(state *chocolateState) {
+	if state == nil {
+		return
+	}
+	if state.RootStyle != "" {
+		c.SelectRootStyle(state.RootStyle)
+	}
+
+	for name, bs := range state.Bars {
+		b, ok := c.bars[barKey(name)]
+		if !ok || bs == nil {
+			continue
+		}
+		if bs.Model != "" {
+			b.selectModel(bs.Model)
+		}
+		for mname, style := range bs.Styles {
+			if model, ok := b.models[strings.ToLower(mname)]; ok {
+				model.selectStyle(style)
+			}
+		}
+		for mname, nested := range bs.Nested {
+			if model, ok := b.models[strings.ToLower(mname)]; ok {
+				if choc, ok := model.source().(*Chocolate); ok {
+					choc.applyState(nested)
+				}
+			}
+		}
+		if bs.Hidden {
+			b.hide()
+		} else if b.isHidden() && !b.isAutoHidden() {
+			b.unhide()
+		}
+	}
+
+	for name, ostate := range state.Overlays {
+		o, ok := c.overlays[name]
+		if !ok || ostate == nil {
+			continue
+		}
+		if ostate.Enabled {
+			o.Enable()
+		} else {
+			o.Disable()
+		}
+		o.Chocolate.applyState(ostate.State)
+	}
+
+	for name, ratio := range state.Splits {
+		c.root.setSplitRatio(name, ratio)
+	}
+
+	for name, v := range state.Vars {
+		c.root.setVar(name, v)
+	}
+
+	c.setDirty()
+}
+
+// SaveState writes the hidden bars, the selected models and styles, the
+// enabled overlays, the ratios of the splits and the layout variables
+// as JSON to the writer.
+// Nested chocolates and overlays are included
+func (c *Chocolate) SaveState(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.state())
+}
+
+// RestoreState reads a state written by SaveState and applies it.
+// Entries for bars, models, overlays or variables that don't exist are ignored
+func (c *Chocolate) RestoreState(r io.Reader) error {
+	state := &chocolateState{}
+	if err := json.NewDecoder(r).Decode(state); err != nil {
+		return err
+	}
+	c.applyState(state)
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package chocolate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const stateLayout = `{
+	"bars": {
+		"Header": {"canhide": true, "models": [{"name": "title", "text": "title"}]},
+		"Menu": {"models": [{"name": "menu", "text": "menu"}]},
+		"Body": {"models": [{"name": "Text", "text": "text"}, {"name": "Help", "text": "help"}], "selected": "text"}
+	},
+	"constraints": [
+		{"target": "header", "target_attribute": "height", "relation": "eq", "var": "header_height", "strength": "required"}
+	],
+	"splits": [{"name": "Sidebar", "first": "Menu", "second": "Body"}]
+}`
+
+func newStateChocolate(t *testing.T) *Chocolate {
+	t.Helper()
+	c := NewChocolate()
+	if err := c.FromJson([]byte(stateLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.MakeOverlay("Dialog", 1, 0.5, 0.5, false)
+	nested, err := c.MakeChocolate("inner", "menu", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested.MakeBar("list", true)
+
+	return c
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	c := newStateChocolate(t)
+	if err := c.Hide("HEADER"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SelectModel("HELP", "body"); err != nil {
+		t.Fatal(err)
+	}
+	c.SetSplitRatio("sidebar", 0.3)
+	c.SetVar("Header_Height", 4)
+	c.overlays["Dialog"].Enable()
+	if err := c.SelectModel("inner", "menu"); err != nil {
+		t.Fatal(err)
+	}
+	nested := c.bars["menu"].models["inner"].source().(*Chocolate)
+	if err := nested.Hide("list"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newStateChocolate(t)
+	if err := restored.RestoreState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if hidden, _ := restored.IsHidden("header"); !hidden {
+		t.Error("header isn't hidden")
+	}
+	body, _ := restored.Bar("Body")
+	if got := body.Selected(); got != "help" {
+		t.Errorf("selected model of body = %q, want help", got)
+	}
+	if got, _ := restored.SplitRatio("sidebar"); got != 0.3 {
+		t.Errorf("split ratio = %v, want 0.3", got)
+	}
+	if got, _ := restored.Var("header_height"); got != 4 {
+		t.Errorf("header_height = %v, want 4", got)
+	}
+	if !restored.overlays["Dialog"].enabled {
+		t.Error("overlay Dialog isn't enabled")
+	}
+	restoredNested := restored.bars["menu"].models["inner"].source().(*Chocolate)
+	if hidden, _ := restoredNested.IsHidden("list"); !hidden {
+		t.Error("bar list of the nested chocolate isn't hidden")
+	}
+}
+
+func TestRestoreStateIgnoresUnknownEntries(t *testing.T) {
+	c := newStateChocolate(t)
+	state := `{
+		"bars": {"nope": {"hidden": true}, "body": {"model": "nope"}},
+		"overlays": {"nope": {"enabled": true}},
+		"splits": {"nope": 0.1}
+	}`
+	if err := c.RestoreState(strings.NewReader(state)); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := c.Bar("body")
+	if got := body.Selected(); got != "text" {
+		t.Errorf("selected model of body = %q, want text", got)
+	}
+	if got := len(c.Bars()); got != 3 {
+		t.Errorf("got %d bars, want 3", got)
+	}
+}
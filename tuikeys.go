@@ -53,3 +53,78 @@ func DefaultKeyMap() *KeyMap {
 		),
 	}
 }
+
+// ScrollKeyMap defines keybindings used by the ScrollModel.
+type ScrollKeyMap struct {
+	// Line wise scrolling.
+	Up    key.Binding
+	Down  key.Binding
+	Left  key.Binding
+	Right key.Binding
+
+	// Page wise scrolling.
+	PageUp   key.Binding
+	PageDown key.Binding
+
+	// Jumping to the start or end.
+	Top    key.Binding
+	Bottom key.Binding
+}
+
+// DefaultScrollKeyMap returns a default set of scroll keybindings.
+func DefaultScrollKeyMap() *ScrollKeyMap {
+	return &ScrollKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "scroll up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "scroll down"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "scroll left"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "scroll right"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("pgup/b", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "f", " "),
+			key.WithHelp("pgdn/f", "page down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("home/g", "go to top"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("end/G", "go to bottom"),
+		),
+	}
+}
+
+// SplitKeyMap defines keybindings used to resize the focused split.
+type SplitKeyMap struct {
+	Grow   key.Binding
+	Shrink key.Binding
+}
+
+// DefaultSplitKeyMap returns a default set of split keybindings.
+func DefaultSplitKeyMap() *SplitKeyMap {
+	return &SplitKeyMap{
+		Grow: key.NewBinding(
+			key.WithKeys("+", "ctrl+right", "ctrl+down"),
+			key.WithHelp("+", "grow split"),
+		),
+		Shrink: key.NewBinding(
+			key.WithKeys("-", "ctrl+left", "ctrl+up"),
+			key.WithHelp("-", "shrink split"),
+		),
+	}
+}
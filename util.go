@@ -110,6 +110,22 @@ func cutLeft(s string, cutWidth int) string {
 	return b.String()
 }
 
+// whichSides expands the sides like lipgloss does for paddings and margins
+func whichSides(i ...int) (top, right, bottom, left int, ok bool) {
+	switch len(i) {
+	case 1:
+		return i[0], i[0], i[0], i[0], true
+	case 2:
+		return i[0], i[1], i[0], i[1], true
+	case 3:
+		return i[0], i[1], i[2], i[1], true
+	case 4:
+		return i[0], i[1], i[2], i[3], true
+	}
+
+	return 0, 0, 0, 0, false
+}
+
 func clamp(v, lower, upper int) int {
 	return min(max(v, lower), upper)
 }
@@ -0,0 +1,138 @@
+package chocolate
+
+import (
+	"strings"
+
+	"github.com/lithdew/casso"
+)
+
+// layoutVar is a named value, that constraints can reference. It is added
+// as edit variable to the solver, so a new value can be suggested to the
+// solver of the last resolve without resolving the whole layout again
+// varPriority is the priority of the edit variables. It is above STRONG,
+// so only required constraints can change the value of a variable
+const varPriority = 10 * casso.Strong
+
+type layoutVar struct {
+	sym     casso.Symbol
+	value   float64
+	pending bool
+	solver  *casso.Solver
+}
+
+func (c *constraintLayout) variable(name string) *layoutVar {
+	name = strings.ToLower(name)
+	if c.vars == nil {
+		c.vars = make(map[string]*layoutVar)
+	}
+	v, ok := c.vars[name]
+	if !ok {
+		v = &layoutVar{sym: casso.New()}
+		c.vars[name] = v
+	}
+
+	return v
+}
+
+// declareVars creates the variables referenced by the constraints
+func (c *constraintLayout) declareVars(constraints []Constraint) {
+	for _, con := range constraints {
+		if con.Var != "" {
+			c.variable(con.Var)
+		}
+	}
+}
+
+// setVar sets the value of the variable. It returns false, if no
+// constraint references the variable
+func (c *constraintLayout) setVar(name string, value float64) bool {
+	v, ok := c.vars[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+	if v.value == value && v.solver != nil {
+		return true
+	}
+	v.value = value
+	v.pending = true
+	c.varsChanged = true
+
+	return true
+}
+
+func (c *constraintLayout) removeVar(name string) {
+	name = strings.ToLower(name)
+	if _, ok := c.vars[name]; ok {
+		delete(c.vars, name)
+		c.setDirty()
+	}
+}
+
+func (c *constraintLayout) getVar(name string) (float64, bool) {
+	if v, ok := c.vars[strings.ToLower(name)]; ok {
+		return v.value, true
+	}
+	return 0, false
+}
+
+// addVars adds all variables as edit variables to the solver. If retain
+// is set, later changes of the variables can be suggested to the solver
+func (c *constraintLayout) addVars(solver *casso.Solver, retain bool) {
+	for name, v := range c.vars {
+		if err := solver.Edit(v.sym, varPriority); err != nil {
+			continue
+		}
+		solver.Suggest(v.sym, v.value)
+		if solver == c.explained {
+			c.applied = append(c.applied, &appliedConstraint{
+				constraintSource: constraintSource{ORIGIN_VAR, "$" + name},
+				priority:         varPriority,
+				variable:         v,
+			})
+		}
+		if retain {
+			v.solver = solver
+			v.pending = false
+		}
+	}
+}
+
+// suggestVars passes the changed variables to the solver of the last resolve
+// and updates the bars. It returns false, if the layout has to be resolved
+// again, because the solver is gone or the result needs the bias heuristic
+func (c *constraintLayout) suggestVars() bool {
+	if c.solver == nil {
+		return false
+	}
+	for _, v := range c.vars {
+		if !v.pending {
+			continue
+		}
+		if v.solver != c.solver {
+			return false
+		}
+		if err := c.solver.Suggest(v.sym, v.value); err != nil {
+			return false
+		}
+		v.pending = false
+	}
+
+	for _, child := range c.children {
+		child.update(c.solver)
+	}
+	if c.bias(c.solver) {
+		c.solver = nil
+		return false
+	}
+	for _, child := range c.children {
+		if child.canBias() && child.anyZero() {
+			return false
+		}
+	}
+	if c.tooSmall() {
+		return false
+	}
+	c.dirty = false
+
+	return true
+}
@@ -0,0 +1,76 @@
+package chocolate
+
+import (
+	"errors"
+	"testing"
+)
+
+const varsLayout = `{
+	"bars": {
+		"Header": {"models": [{"name": "title", "text": "title"}]},
+		"Body": {"models": [{"name": "text", "text": "text"}]}
+	},
+	"constraints": [
+		{"target": "header", "target_attribute": "height", "relation": "eq", "var": "Header_Height", "strength": "required"},
+		{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 2, "strength": "strong"},
+		{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 2, "strength": "strong"},
+		{"target": "header", "target_attribute": "width", "source": "super", "source_attribute": "width", "relation": "eq", "strength": "required"},
+		{"target": "body", "target_attribute": "ystart", "source": "header", "source_attribute": "yend", "relation": "eq", "strength": "required"},
+		{"target": "body", "target_attribute": "yend", "source": "super", "source_attribute": "yend", "relation": "eq", "strength": "required"}
+	]
+}`
+
+func TestSetVar(t *testing.T) {
+	c := NewChocolate()
+	if err := c.FromJson([]byte(varsLayout)); err != nil {
+		t.Fatal(err)
+	}
+	c.Resize(40, 20)
+
+	for _, height := range []float64{5, 8, 3} {
+		if err := c.SetVar("HEADER_HEIGHT", height); err != nil {
+			t.Fatal(err)
+		}
+		c.View()
+		// the variable wins over the strong constraints, even if
+		// they outweigh a single strong one together
+		if got := c.bars["header"].height(); got != int(height) {
+			t.Errorf("header height = %d, want %v", got, height)
+		}
+		if got := c.bars["body"].height(); got != 20-int(height) {
+			t.Errorf("body height = %d, want %v", got, 20-height)
+		}
+		if got, _ := c.Var("header_height"); got != height {
+			t.Errorf("Var = %v, want %v", got, height)
+		}
+	}
+
+	if err := c.SetVar("nope", 1); !errors.Is(err, ErrUnknownVar) {
+		t.Errorf("SetVar of an unknown variable: got %v, want %v", err, ErrUnknownVar)
+	}
+	if _, ok := c.Var("nope"); ok {
+		t.Error("SetVar created the unknown variable")
+	}
+}
+
+func TestSetVarOfGoConstraint(t *testing.T) {
+	c := NewChocolate()
+	if _, err := c.MakeBar("sidebar", false).MakeText("text", true); err != nil {
+		t.Fatal(err)
+	}
+	c.AddConstraints(NewConstraint().
+		WithTarget("sidebar").
+		WithTargetAttribute(WIDTH).
+		WithRelation(EQ).
+		WithVar("sidebar").
+		WithStrength(REQUIRED))
+	c.Resize(80, 10)
+
+	if err := c.SetVar("sidebar", 30); err != nil {
+		t.Fatal(err)
+	}
+	c.View()
+	if got := c.bars["sidebar"].width(); got != 30 {
+		t.Errorf("sidebar width = %d, want 30", got)
+	}
+}
@@ -0,0 +1,166 @@
+package chocolate
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LayoutReloadedMsg is sent after a watched layout file has changed and was
+// loaded again. If the file couldn't be loaded, Err is set and the previous
+// layout stays active
+type LayoutReloadedMsg struct {
+	File string
+	Err  error
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampFile(file string) (fileStamp, bool) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, true
+}
+
+type layoutWatch struct {
+	file     string
+	interval time.Duration
+	// stamps of the layout file and all files it includes
+	stamps  map[string]fileStamp
+	stopped bool
+}
+
+// layoutPollMsg is the result of polling the files of a watch
+type layoutPollMsg struct {
+	watch   *layoutWatch
+	changed bool
+}
+
+func (lw *layoutWatch) poll() tea.Cmd {
+	if lw.stopped {
+		return nil
+	}
+
+	file := lw.file
+	stamps := make(map[string]fileStamp, len(lw.stamps))
+	for k, v := range lw.stamps {
+		stamps[k] = v
+	}
+	return tea.Tick(lw.interval, func(time.Time) tea.Msg {
+		ret := layoutPollMsg{watch: lw}
+		if len(stamps) == 0 {
+			_, ret.changed = stampFile(file)
+		}
+		for f, old := range stamps {
+			if stamp, ok := stampFile(f); !ok || !stamp.modTime.Equal(old.modTime) || stamp.size != old.size {
+				ret.changed = true
+				break
+			}
+		}
+
+		return ret
+	})
+}
+
+// WatchFile loads the layout file and polls it and the files it includes
+// in the interval for changes. A change replaces the layout and a
+// LayoutReloadedMsg is sent. The result of the first load is sent as
+// LayoutReloadedMsg as well. The returned command has to be passed to
+// bubbletea and the messages have to be forwarded to Update
+func (c *Chocolate) WatchFile(file string, interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if c.watches == nil {
+		c.watches = make(map[string]*layoutWatch)
+	}
+	if w, ok := c.watches[file]; ok {
+		w.stopped = true
+	}
+
+	w := &layoutWatch{
+		file:     file,
+		interval: interval,
+	}
+	c.watches[file] = w
+
+	return c.load(w)
+}
+
+// StopWatch stops polling the file
+func (c *Chocolate) StopWatch(file string) {
+	if w, ok := c.watches[file]; ok {
+		w.stopped = true
+		delete(c.watches, file)
+	}
+}
+
+// reload applies the polled file. Messages of watches started on nested
+// chocolates or overlays are passed on to them
+func (c *Chocolate) reload(msg layoutPollMsg) tea.Cmd {
+	w := msg.watch
+	if w.stopped {
+		return nil
+	}
+	if c.watches[w.file] != w {
+		for _, nested := range c.nested() {
+			if cmd := nested.reload(msg); cmd != nil {
+				return cmd
+			}
+		}
+		return nil
+	}
+	if !msg.changed {
+		return w.poll()
+	}
+
+	return c.load(w)
+}
+
+// load applies the file of the watch and polls it again
+func (c *Chocolate) load(w *layoutWatch) tea.Cmd {
+	files, err := c.fromFile(w.file)
+	if len(files) == 0 {
+		files = []string{w.file}
+	}
+	// the stamps are updated on errors as well, so a broken
+	// file is reported only once
+	w.stamps = make(map[string]fileStamp, len(files))
+	for _, f := range files {
+		if stamp, ok := stampFile(f); ok {
+			w.stamps[f] = stamp
+		}
+	}
+	reloaded := LayoutReloadedMsg{
+		File: w.file,
+		Err:  err,
+	}
+
+	return tea.Batch(
+		func() tea.Msg { return reloaded },
+		w.poll(),
+	)
+}
+
+// nested returns the chocolates of the bars and the overlays
+func (c *Chocolate) nested() []*Chocolate {
+	ret := []*Chocolate{}
+	for _, b := range c.bars {
+		for _, model := range b.models {
+			if nested, ok := model.source().(*Chocolate); ok {
+				ret = append(ret, nested)
+			}
+		}
+	}
+	for _, o := range c.overlays {
+		ret = append(ret, &o.Chocolate)
+	}
+
+	return ret
+}
@@ -0,0 +1,72 @@
+package chocolate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFileReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "layout.cnf")
+	write := func(layout string) {
+		if err := os.WriteFile(file, []byte(layout), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{
+		"padding": [1],
+		"gap": 1,
+		"bars": {
+			"header": {"models": [{"name": "title", "text": "title"}, {"name": "status", "text": "status"}]},
+			"sidebar": {"models": [{"name": "menu", "text": "menu"}]}
+		},
+		"constraints": [
+			{"target": "header", "target_attribute": "height", "relation": "eq", "var": "header_height"},
+			{"target": "sidebar", "target_attribute": "width", "relation": "eq", "constant": 10}
+		]
+	}`)
+
+	c := NewChocolate()
+	c.WatchFile(file, time.Hour)
+	defer c.StopWatch(file)
+	if !c.IsBar("sidebar") {
+		t.Fatal("WatchFile didn't load the file")
+	}
+	if _, ok := c.Var("header_height"); !ok {
+		t.Fatal("variable header_height is missing")
+	}
+
+	write(`{
+		"bars": {
+			"header": {"models": [{"name": "title", "text": "title"}]}
+		},
+		"constraints": [
+			{"target": "header", "target_attribute": "height", "relation": "eq", "constant": 3}
+		]
+	}`)
+	c.Update(layoutPollMsg{watch: c.watches[file], changed: true})
+
+	if c.IsBar("sidebar") {
+		t.Error("bar sidebar wasn't removed")
+	}
+	header, err := c.Bar("header")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.getModel("header", "status"); err == nil {
+		t.Error("model status wasn't removed")
+	}
+	if got := header.Selected(); got != "title" {
+		t.Errorf("selected model = %q, want title", got)
+	}
+	if _, ok := c.Var("header_height"); ok {
+		t.Error("variable header_height wasn't removed")
+	}
+	if c.root.padding != [4]int{} {
+		t.Errorf("padding = %v, want the default", c.root.padding)
+	}
+	if c.root.gap != 0 {
+		t.Errorf("gap = %d, want the default", c.root.gap)
+	}
+}